@@ -0,0 +1,78 @@
+// Утилита заполняет дату добавления (createdAt) у фильмов каталога, где она не указана.
+// Дата берётся из первого коммита git, в котором появился id фильма; если историю
+// получить не удалось — ставится текущее время, то есть дата импорта.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"movie-catalog/internal/history"
+)
+
+func main() {
+	path := flag.String("catalog", "static/data/movies.json", "путь к JSON-файлу каталога")
+	dryRun := flag.Bool("dry-run", false, "только показать найденные даты, не изменяя файл")
+	historyPath := flag.String("history", "data/history.jsonl", "путь к журналу правок фильмов")
+	author := flag.String("author", os.Getenv("USER"), "автор правок для журнала")
+	flag.Parse()
+
+	draft, err := history.ReadCatalog(*path)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	moviesByCategory := draft.Movies
+
+	importDate := time.Now().UTC().Truncate(time.Second)
+	filled := 0
+	for category, movies := range moviesByCategory {
+		for i := range movies {
+			if !movies[i].CreatedAt.IsZero() {
+				continue
+			}
+			created, source := firstCommitDate(*path, movies[i].ID), "git"
+			if created.IsZero() {
+				created, source = importDate, "дата импорта"
+			}
+			movies[i].CreatedAt = created
+			filled++
+			fmt.Printf("%s/%s: %s (%s)\n", category, movies[i].ID, created.Format(time.RFC3339), source)
+		}
+	}
+
+	if filled == 0 {
+		fmt.Println("У всех фильмов уже указана дата добавления")
+		return
+	}
+	if *dryRun {
+		fmt.Printf("Найдено дат: %d, файл не изменён\n", filled)
+		return
+	}
+
+	if _, err := history.New(*historyPath).Save(draft, history.Author{Name: *author, Source: "backfill-created"}); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Заполнено дат: %d\n", filled)
+}
+
+// firstCommitDate возвращает дату первого коммита, добавившего id фильма в файл каталога,
+// или нулевое время, если git недоступен или фильм ещё не закоммичен
+func firstCommitDate(path, id string) time.Time {
+	needle := fmt.Sprintf("%q: %q", "id", id)
+	out, err := exec.Command("git", "log", "--reverse", "--format=%aI", "-S", needle, "--", path).Output()
+	if err != nil {
+		return time.Time{}
+	}
+	first, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	created, err := time.Parse(time.RFC3339, first)
+	if err != nil {
+		return time.Time{}
+	}
+	return created.UTC()
+}
@@ -0,0 +1,54 @@
+// Утилита проверяет файлы каталога теми же правилами, что и сервер при загрузке:
+// идентификаторы, годы, категории, внешние идентификаторы и ссылки, переводы,
+// а также список людей people.json. Удобно запускать перед коммитом изменений.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"movie-catalog/internal/catalog"
+)
+
+func main() {
+	path := flag.String("catalog", "static/data/movies.json", "путь к JSON-файлу каталога")
+	flag.Parse()
+
+	moviesByCategory, err := catalog.ReadFile(*path)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	peoplePath := filepath.Join(filepath.Dir(*path), "people.json")
+	people, err := catalog.ReadPeople(peoplePath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	var problems []error
+	for _, err := range []error{catalog.Validate(moviesByCategory), catalog.ValidatePeople(people)} {
+		var joined interface{ Unwrap() []error }
+		if errors.As(err, &joined) {
+			problems = append(problems, joined.Unwrap()...)
+		} else if err != nil {
+			problems = append(problems, err)
+		}
+	}
+	if len(problems) > 0 {
+		for _, e := range problems {
+			fmt.Println(e)
+		}
+		fmt.Printf("Найдено ошибок: %d\n", len(problems))
+		os.Exit(1)
+	}
+
+	count := 0
+	for _, movies := range moviesByCategory {
+		count += len(movies)
+	}
+	fmt.Printf("%s: ошибок нет, фильмов: %d, людей: %d\n", *path, count, len(people))
+}
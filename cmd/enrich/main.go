@@ -0,0 +1,195 @@
+// Утилита дополняет каталог метаданными из внешнего источника (OMDb).
+// Заполняются только пустые поля: описания, постер, продолжительность, оригинальное
+// название, режиссёры, актёры, страны и внешние идентификаторы; уже заданные значения
+// не меняются. Ответы источника кэшируются на диске.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"movie-catalog/internal/catalog"
+	"movie-catalog/internal/enrich"
+	"movie-catalog/internal/history"
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/models"
+)
+
+func main() {
+	path := flag.String("catalog", "static/data/movies.json", "путь к JSON-файлу каталога")
+	apiKey := flag.String("api-key", os.Getenv("OMDB_API_KEY"), "ключ OMDb API (по умолчанию из OMDB_API_KEY)")
+	apiURL := flag.String("api-url", enrich.OMDbURL, "адрес OMDb API")
+	cacheDir := flag.String("cache-dir", ".cache/enrich", "каталог для кэша ответов")
+	cacheTTL := flag.Duration("cache-ttl", 30*24*time.Hour, "срок хранения ответов в кэше")
+	only := flag.String("only", "", "обработать только фильм с этим id")
+	dryRun := flag.Bool("dry-run", false, "только показать изменения, не изменяя файл")
+	historyPath := flag.String("history", "data/history.jsonl", "путь к журналу правок фильмов")
+	author := flag.String("author", os.Getenv("USER"), "автор правок для журнала")
+	flag.Parse()
+
+	if *apiKey == "" {
+		fmt.Println("Не указан ключ OMDb API: задайте -api-key или OMDB_API_KEY")
+		os.Exit(2)
+	}
+
+	// Пока идут запросы к источнику, файл могут править вручную: при записи
+	// переносятся только поля, заполненные утилитой
+	draft, err := history.ReadCatalog(*path)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	moviesByCategory := draft.Movies
+
+	// Имена людей из источника переводятся по people.json рядом с каталогом
+	people, err := catalog.ReadPeople(filepath.Join(filepath.Dir(*path), "people.json"))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	dict := newNames(people)
+
+	omdb := enrich.NewOMDb(*apiKey)
+	omdb.BaseURL = *apiURL
+	provider := enrich.NewCache(omdb, *cacheDir, *cacheTTL)
+	ctx := context.Background()
+
+	categories := make([]string, 0, len(moviesByCategory))
+	for category := range moviesByCategory {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	changed, failed := 0, 0
+	for _, category := range categories {
+		movies := moviesByCategory[category]
+		for i := range movies {
+			if *only != "" && movies[i].ID != *only {
+				continue
+			}
+			meta, err := provider.Fetch(ctx, lookupFor(movies[i]))
+			if errors.Is(err, enrich.ErrNotFound) {
+				fmt.Printf("%s/%s: не найден в %s\n", category, movies[i].ID, provider.Name())
+				continue
+			}
+			if err != nil {
+				fmt.Printf("%s/%s: %v\n", category, movies[i].ID, err)
+				failed++
+				continue
+			}
+			if fields := apply(&movies[i], meta, dict); len(fields) > 0 {
+				changed++
+				fmt.Printf("%s/%s: %s\n", category, movies[i].ID, strings.Join(fields, ", "))
+			}
+		}
+	}
+
+	if changed == 0 {
+		fmt.Println("Новых данных не найдено")
+	} else if *dryRun {
+		fmt.Printf("Можно дополнить фильмов: %d, файл не изменён\n", changed)
+	} else if _, err := history.New(*historyPath).Save(draft, history.Author{Name: *author, Source: "enrich"}); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	} else {
+		fmt.Printf("Дополнено фильмов: %d\n", changed)
+	}
+	if failed > 0 {
+		fmt.Printf("Ошибок запроса: %d\n", failed)
+		os.Exit(1)
+	}
+}
+
+// lookupFor собирает условия поиска фильма. Источники обычно знают английское
+// название, поэтому оно предпочтительнее русского.
+func lookupFor(movie models.Movie) enrich.Lookup {
+	lookup := enrich.Lookup{Title: movie.Title, Year: movie.Year, ExternalIDs: movie.ExternalIDs}
+	if tr, ok := movie.Translations["en"]; ok && tr.Title != "" {
+		lookup.Title = tr.Title
+	}
+	return lookup
+}
+
+// apply заполняет пустые поля фильма данными источника и возвращает список изменённых полей.
+// dict переводит имена и страны на язык каталога.
+func apply(movie *models.Movie, meta enrich.Metadata, dict *names) []string {
+	var fields []string
+	set := func(field string, dst *string, value string) {
+		if *dst == "" && value != "" {
+			*dst = value
+			fields = append(fields, field)
+		}
+	}
+
+	if meta.Language == i18n.Default {
+		set("description", &movie.Description, firstSentence(meta.Description))
+		set("fullDescription", &movie.FullDescription, meta.Description)
+	} else if i18n.Supports(meta.Language) {
+		tr := movie.Translations[meta.Language]
+		prefix := "translations." + meta.Language + "."
+		set(prefix+"title", &tr.Title, meta.Title)
+		set(prefix+"description", &tr.Description, firstSentence(meta.Description))
+		set(prefix+"fullDescription", &tr.FullDescription, meta.Description)
+		if tr != (models.MovieTranslation{}) {
+			if movie.Translations == nil {
+				movie.Translations = make(map[string]models.MovieTranslation)
+			}
+			movie.Translations[meta.Language] = tr
+		}
+	}
+	set("imagePath", &movie.ImagePath, meta.PosterURL)
+	if movie.Runtime == 0 && meta.Runtime > 0 {
+		movie.Runtime = meta.Runtime
+		fields = append(fields, "runtime")
+	}
+
+	// Название из англоязычного источника у зарубежных фильмов обычно совпадает
+	// с оригинальным; у остальных его стоит проверить по отчёту утилиты
+	if meta.Language != i18n.Default {
+		set("originalTitle", &movie.OriginalTitle, meta.Title)
+	}
+
+	// Имена и страны в каталоге пишутся по-русски, чтобы фильтр «все фильмы Нолана»
+	// находил их одинаково, поэтому значения источника сначала переводятся
+	setList := func(field string, dst *[]string, value []string, ok bool) {
+		if ok && len(*dst) == 0 && len(value) > 0 {
+			*dst = value
+			fields = append(fields, field)
+		}
+	}
+	directors, ok := dict.persons(meta.Language, meta.Directors)
+	setList("directors", &movie.Directors, directors, ok)
+	cast, ok := dict.persons(meta.Language, meta.Cast)
+	setList("cast", &movie.Cast, cast, ok)
+	countries, ok := dict.countries(meta.Language, meta.Countries)
+	setList("countries", &movie.Countries, countries, ok)
+
+	for provider, id := range meta.ExternalIDs {
+		if movie.ExternalIDs[provider] != "" {
+			continue
+		}
+		if movie.ExternalIDs == nil {
+			movie.ExternalIDs = make(map[string]string)
+		}
+		movie.ExternalIDs[provider] = id
+		fields = append(fields, "externalIds."+provider)
+	}
+	return fields
+}
+
+// firstSentence возвращает первое предложение текста — для краткого описания
+func firstSentence(text string) string {
+	for i, r := range text {
+		if (r == '.' || r == '!' || r == '?') && (i+1 == len(text) || text[i+1] == ' ') {
+			return text[:i+1]
+		}
+	}
+	return text
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/models"
+)
+
+// countryNames — русские названия стран в том виде, в каком их пишет OMDb
+var countryNames = map[string]string{
+	"argentina":            "Аргентина",
+	"australia":            "Австралия",
+	"austria":              "Австрия",
+	"belgium":              "Бельгия",
+	"brazil":               "Бразилия",
+	"canada":               "Канада",
+	"china":                "Китай",
+	"czech republic":       "Чехия",
+	"denmark":              "Дания",
+	"finland":              "Финляндия",
+	"france":               "Франция",
+	"germany":              "Германия",
+	"hong kong":            "Гонконг",
+	"hungary":              "Венгрия",
+	"iceland":              "Исландия",
+	"india":                "Индия",
+	"ireland":              "Ирландия",
+	"italy":                "Италия",
+	"japan":                "Япония",
+	"jordan":               "Иордания",
+	"malta":                "Мальта",
+	"mexico":               "Мексика",
+	"morocco":              "Марокко",
+	"netherlands":          "Нидерланды",
+	"new zealand":          "Новая Зеландия",
+	"norway":               "Норвегия",
+	"poland":               "Польша",
+	"russia":               "Россия",
+	"south africa":         "ЮАР",
+	"south korea":          "Южная Корея",
+	"soviet union":         "СССР",
+	"spain":                "Испания",
+	"sweden":               "Швеция",
+	"switzerland":          "Швейцария",
+	"taiwan":               "Тайвань",
+	"united arab emirates": "ОАЭ",
+	"united kingdom":       "Великобритания",
+	"united states":        "США",
+	"west germany":         "ФРГ",
+}
+
+// names переводит имена людей и названия стран из источника на русский, как они
+// записаны в каталоге. Имена людей берутся из поля names файла people.json.
+type names struct {
+	// people — язык → имя на этом языке в нижнем регистре → имя в каталоге
+	people map[string]map[string]string
+}
+
+func newNames(people []models.Person) *names {
+	n := &names{people: make(map[string]map[string]string)}
+	for _, person := range people {
+		for lang, name := range person.Names {
+			if n.people[lang] == nil {
+				n.people[lang] = make(map[string]string)
+			}
+			n.people[lang][strings.ToLower(name)] = person.Name
+		}
+	}
+	return n
+}
+
+// persons переводит имена с языка lang. ok равен false, если хотя бы одно имя
+// неизвестно: неполный список режиссёров или актёров хуже пустого.
+func (n *names) persons(lang string, list []string) (result []string, ok bool) {
+	if lang == i18n.Default {
+		return list, true
+	}
+	return translate(list, n.people[lang])
+}
+
+// countries переводит названия стран; известны только английские
+func (n *names) countries(lang string, list []string) ([]string, bool) {
+	switch lang {
+	case i18n.Default:
+		return list, true
+	case "en":
+		return translate(list, countryNames)
+	}
+	return nil, false
+}
+
+func translate(list []string, dict map[string]string) ([]string, bool) {
+	result := make([]string, 0, len(list))
+	for _, value := range list {
+		translated, ok := dict[strings.ToLower(value)]
+		if !ok {
+			return nil, false
+		}
+		result = append(result, translated)
+	}
+	return result, true
+}
@@ -0,0 +1,73 @@
+// Утилита для предварительного сжатия статических файлов
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Файлы, для которых создаются сжатые версии .gz и .br
+var staticFiles = []string{
+	filepath.Join("static", "css", "styles.css"),
+	filepath.Join("static", "js", "main.js"),
+	filepath.Join("static", "data", "movies.json"),
+}
+
+func main() {
+	for _, path := range staticFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Printf("Ошибка при чтении файла %s: %v\n", path, err)
+			os.Exit(1)
+		}
+
+		gzData, err := compress(data, func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriterLevel(w, gzip.BestCompression)
+		})
+		if err != nil {
+			fmt.Printf("Ошибка при gzip-сжатии %s: %v\n", path, err)
+			os.Exit(1)
+		}
+
+		brData, err := compress(data, func(w io.Writer) (io.WriteCloser, error) {
+			return brotli.NewWriterLevel(w, brotli.BestCompression), nil
+		})
+		if err != nil {
+			fmt.Printf("Ошибка при brotli-сжатии %s: %v\n", path, err)
+			os.Exit(1)
+		}
+
+		if err := os.WriteFile(path+".gz", gzData, 0644); err != nil {
+			fmt.Printf("Ошибка при записи %s.gz: %v\n", path, err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(path+".br", brData, 0644); err != nil {
+			fmt.Printf("Ошибка при записи %s.br: %v\n", path, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("%s: %d байт -> gzip %d, brotli %d\n", path, len(data), len(gzData), len(brData))
+	}
+}
+
+// compress сжимает данные кодировщиком, созданным newWriter
+func compress(data []byte, newWriter func(io.Writer) (io.WriteCloser, error)) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := newWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
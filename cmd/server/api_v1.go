@@ -0,0 +1,176 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/api"
+	"movie-catalog/internal/catalog"
+	"movie-catalog/internal/external"
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/models"
+)
+
+// Префикс API v1
+const apiV1Prefix = "/api/v1"
+
+// listMeta возвращает метаданные для списка из count элементов
+func listMeta(count int) *api.Meta {
+	return &api.Meta{Count: count, CatalogVersion: movieCatalog.Version()}
+}
+
+// itemMeta возвращает метаданные для одиночного объекта
+func itemMeta() *api.Meta {
+	return listMeta(1)
+}
+
+// categoryList собирает список категорий с количеством фильмов и названиями на языке locale
+func categoryList(locale string) []models.Category {
+	sizes := movieCatalog.Sizes()
+	categories := make([]models.Category, 0, len(sizes))
+	for _, key := range movieCatalog.Categories() {
+		categories = append(categories, models.Category{
+			Key:   key,
+			Name:  i18n.CategoryName(locale, key),
+			Count: sizes[key],
+		})
+	}
+	return categories
+}
+
+// localizeMovies возвращает фильмы с названиями и описаниями на языке locale
+func localizeMovies(movies []models.Movie, locale string) []models.Movie {
+	localized := make([]models.Movie, len(movies))
+	for i, movie := range movies {
+		localized[i] = movie.Localized(locale)
+	}
+	return localized
+}
+
+// movieQuery собирает условия выборки из параметров запроса:
+// category, q, director, actor, country, yearFrom, yearTo, runtimeFrom, runtimeTo, maxAge,
+// tags и tagMode (all — нужны все метки, any — хотя бы одна).
+// false — числовой параметр или tagMode некорректны.
+func movieQuery(c *gin.Context) (catalog.Query, bool) {
+	q := catalog.Query{
+		Category: c.Query("category"),
+		Text:     strings.TrimSpace(c.Query("q")),
+		Director: strings.TrimSpace(c.Query("director")),
+		Actor:    strings.TrimSpace(c.Query("actor")),
+		Country:  strings.TrimSpace(c.Query("country")),
+		Tags:     queryTags(c),
+	}
+	switch c.DefaultQuery("tagMode", "all") {
+	case "all":
+	case "any":
+		q.AnyTag = true
+	default:
+		return q, false
+	}
+	for name, dst := range map[string]*int{
+		"yearFrom":    &q.YearFrom,
+		"yearTo":      &q.YearTo,
+		"runtimeFrom": &q.RuntimeFrom,
+		"runtimeTo":   &q.RuntimeTo,
+	} {
+		if value := c.Query(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return q, false
+			}
+			*dst = n
+		}
+	}
+	if value := c.Query("maxAge"); value != "" {
+		age, err := strconv.Atoi(value)
+		if err != nil || age < 0 {
+			return q, false
+		}
+		q.MaxAge = &age
+	}
+	return q, true
+}
+
+// Обработчик API v1 для списка фильмов с фильтрами из movieQuery.
+// При поиске по q выше оказываются совпадения в названии.
+func handleV1Movies(c *gin.Context) {
+	q, ok := movieQuery(c)
+	if !ok {
+		api.Fail(c, http.StatusBadRequest, api.CodeBadRequest)
+		return
+	}
+	if q.Category != "" {
+		if _, ok := movieCatalog.Category(q.Category); !ok {
+			api.Fail(c, http.StatusNotFound, api.CodeCategoryNotFound)
+			return
+		}
+	}
+
+	movies := movieCatalog.Find(q)
+	api.OK(c, localizeMovies(movies, api.Locale(c)), listMeta(len(movies)))
+}
+
+// Обработчик API v1 для одного фильма
+func handleV1Movie(c *gin.Context) {
+	movie, ok := movieCatalog.Movie(c.Param("id"))
+	if !ok {
+		api.Fail(c, http.StatusNotFound, api.CodeMovieNotFound)
+		return
+	}
+	api.OK(c, movie.Localized(api.Locale(c)), itemMeta())
+}
+
+// Обработчик API v1 для поиска фильма по идентификатору во внешнем сервисе,
+// например /api/v1/movies/by-external/kinopoisk/1044002
+func handleV1MovieByExternal(c *gin.Context) {
+	provider := external.Provider(c.Param("provider"))
+	if !provider.Known() {
+		api.Fail(c, http.StatusBadRequest, api.CodeUnknownProvider)
+		return
+	}
+	movie, ok := movieCatalog.ByExternalID(string(provider), c.Param("id"))
+	if !ok {
+		api.Fail(c, http.StatusNotFound, api.CodeMovieNotFound)
+		return
+	}
+	api.OK(c, movie.Localized(api.Locale(c)), itemMeta())
+}
+
+// Обработчик API v1 для списка категорий
+func handleV1Categories(c *gin.Context) {
+	categories := categoryList(api.Locale(c))
+	api.OK(c, categories, listMeta(len(categories)))
+}
+
+// Обработчик API v1 для фильмов категории
+func handleV1CategoryMovies(c *gin.Context) {
+	movies, ok := movieCatalog.Category(c.Param("category"))
+	if !ok {
+		api.Fail(c, http.StatusNotFound, api.CodeCategoryNotFound)
+		return
+	}
+	api.OK(c, localizeMovies(movies, api.Locale(c)), listMeta(len(movies)))
+}
+
+// handleNoRoute отвечает на запросы к несуществующим маршрутам:
+// для API v1 — ошибкой в конверте, для остальных — обычным 404
+func handleNoRoute(c *gin.Context) {
+	if strings.HasPrefix(c.Request.URL.Path, apiV1Prefix+"/") {
+		api.Fail(c, http.StatusNotFound, api.CodeRouteNotFound)
+		return
+	}
+	c.String(http.StatusNotFound, "404 page not found")
+}
+
+// handleRateLimited отвечает на запрос сверх лимита частоты:
+// для API v1 — ошибкой в конверте, для остальных — JSON с сообщением
+func handleRateLimited(c *gin.Context) {
+	if strings.HasPrefix(c.Request.URL.Path, apiV1Prefix+"/") {
+		api.Fail(c, http.StatusTooManyRequests, api.CodeRateLimited)
+		return
+	}
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "Слишком много запросов, попробуйте позже"})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/api"
+	"movie-catalog/internal/catalog"
+	"movie-catalog/internal/config"
+)
+
+// v1Router поднимает роутер сервера над каталогом из одного фильма
+func v1Router(t *testing.T, cfg config.Config) *gin.Engine {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "movies.json")
+	data := `{"drama": [{"id": "inception", "title": "Начало", "year": 2010, "category": "drama", "description": "Сон во сне", "tags": ["dreams"]}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	movieCatalog = catalog.New(path)
+	if err := movieCatalog.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	router, err := setupRouter(slog.New(slog.NewTextHandler(io.Discard, nil)), cfg)
+	if err != nil {
+		t.Fatalf("setupRouter: %v", err)
+	}
+	return router
+}
+
+// TestV1VaryKeepsCookie проверяет, что конверт API v1 не затирает Vary: Cookie,
+// с которым DetectLocale отмечает выбор языка по cookie
+func TestV1VaryKeepsCookie(t *testing.T) {
+	router := v1Router(t, config.Load())
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/tags?lang=en", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("статус %d: %s", recorder.Code, recorder.Body)
+	}
+	vary := make(map[string]bool)
+	for _, value := range recorder.Header().Values("Vary") {
+		for _, item := range strings.Split(value, ",") {
+			vary[strings.TrimSpace(item)] = true
+		}
+	}
+	for _, want := range []string{"Cookie", "Accept", "Accept-Language"} {
+		if !vary[want] {
+			t.Errorf("в Vary нет %s: %q", want, recorder.Header().Values("Vary"))
+		}
+	}
+}
+
+// TestV1RateLimitEnvelope проверяет, что превышение лимита в API v1 отдаётся
+// ошибкой в конверте, а не прежним JSON
+func TestV1RateLimitEnvelope(t *testing.T) {
+	cfg := config.Load()
+	cfg.RateLimits.API = config.RateLimit{RPS: 0.001, Burst: 1}
+	router := v1Router(t, cfg)
+
+	var recorder *httptest.ResponseRecorder
+	for i := 0; i < 2; i++ {
+		recorder = httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/categories", nil)
+		request.Header.Set("Accept-Language", "en")
+		router.ServeHTTP(recorder, request)
+	}
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("статус %d, ожидался 429: %s", recorder.Code, recorder.Body)
+	}
+	if recorder.Header().Get("Retry-After") == "" {
+		t.Error("нет заголовка Retry-After")
+	}
+	var envelope api.ErrorResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("тело не в конверте: %v: %s", err, recorder.Body)
+	}
+	if envelope.Error.Code != api.CodeRateLimited || envelope.Error.Message != "Too many requests, please try again later" {
+		t.Errorf("ошибка %+v", envelope.Error)
+	}
+}
@@ -0,0 +1,793 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/api"
+	"movie-catalog/internal/external"
+	"movie-catalog/internal/models"
+	"movie-catalog/internal/movienight"
+	"movie-catalog/internal/openapi"
+)
+
+// buildAPISpec описывает все маршруты /api. Схемы строятся из моделей,
+// а соответствие маршрутам роутера проверяется тестом TestOpenAPIMatchesRoutes.
+func buildAPISpec() *openapi.Document {
+	schemas := openapi.NewRegistry()
+	movie := schemas.Register("Movie", models.Movie{})
+	category := schemas.Register("Category", models.Category{})
+	tag := schemas.Register("Tag", models.Tag{})
+	similarMovie := schemas.Register("SimilarMovie", models.SimilarMovie{})
+	schemas.Register("Credit", models.Credit{})
+	personDetails := schemas.Register("PersonDetails", models.PersonDetails{})
+	collection := schemas.Register("Collection", models.Collection{})
+	collectionDetails := schemas.Register("CollectionDetails", models.CollectionDetails{})
+	collectionInput := schemas.Register("CollectionInput", collectionInput{})
+	userRating := schemas.Register("UserRating", models.UserRating{})
+	ratingInput := schemas.Register("RatingInput", ratingInput{})
+	watchlistItem := schemas.Register("WatchlistItem", models.WatchlistItem{})
+	recommendation := schemas.Register("Recommendation", models.Recommendation{})
+	movieNight := schemas.Register("MovieNight", models.MovieNight{})
+	schemas.Register("NightTally", models.NightTally{})
+	schemas.Register("NightRound", models.NightRound{})
+	nightResults := schemas.Register("NightResults", models.NightResults{})
+	nightInput := schemas.Register("NightInput", nightInput{})
+	ballotInput := schemas.Register("BallotInput", ballotInput{})
+	schemas.Register("FieldChange", models.FieldChange{})
+	movieRevision := schemas.Register("MovieRevision", models.MovieRevision{})
+	revertInput := schemas.Register("RevertInput", revertInput{})
+	apiError := schemas.Register("Error", models.ErrorResponse{})
+	meta := schemas.Register("Meta", api.Meta{})
+	schemas.Register("APIError", api.Error{})
+	errorEnvelope := schemas.Register("ErrorEnvelope", api.ErrorResponse{})
+
+	schemas.Describe("Movie", "id", "Уникальный идентификатор фильма")
+	schemas.Describe("Movie", "category", "Ключ категории, например drama")
+	schemas.Describe("Movie", "description", "Краткое описание")
+	schemas.Describe("Movie", "fullDescription", "Полное описание")
+	schemas.Describe("Movie", "imagePath", "Адрес постера")
+	schemas.Describe("Movie", "link", "Ссылка на страницу фильма на Кинопоиске")
+	schemas.Describe("Movie", "originalTitle", "Название на языке оригинала")
+	schemas.Describe("Movie", "runtime", "Продолжительность в минутах")
+	schemas.Describe("Movie", "cast", "Актёры главных ролей")
+	schemas.Enum("Movie", "ageRating", models.AgeRatings)
+	schemas.Describe("Movie", "tags", "Метки тем: латиница в нижнем регистре, цифры и дефисы")
+	schemas.Describe("Movie", "credits", "Режиссёры и актёры, у которых есть страница; заполняется сервером")
+	schemas.Enum("Credit", "role", []string{models.RoleDirector, models.RoleActor})
+	schemas.Describe("PersonDetails", "photo", "Адрес фотографии")
+	schemas.Describe("PersonDetails", "movies", "Фильмы каталога с участием человека, от новых к старым, с его ролями")
+	schemas.Describe("RatingInput", "rating", fmt.Sprintf("Оценка от %d до %d", models.MinRating, models.MaxRating))
+	schemas.Describe("UserRating", "rating", fmt.Sprintf("Оценка от %d до %d", models.MinRating, models.MaxRating))
+	schemas.Enum("Recommendation", "reason", []string{models.ReasonCollaborative, models.ReasonSimilar, models.ReasonPopular})
+	schemas.Describe("Recommendation", "score", "Вес рекомендации; сравним только между рекомендациями с одинаковым reason")
+	schemas.Describe("Recommendation", "becauseOf", "Фильм зрителя, на который похож рекомендованный (для reason=similar)")
+	schemas.Enum("MovieNight", "method", []string{models.VotingApproval, models.VotingRanked})
+	schemas.Describe("MovieNight", "id", "Идентификатор из ссылки на голосование")
+	schemas.Describe("MovieNight", "movieIds", "Фильмы-кандидаты в порядке создателя; при равенстве голосов выше тот, что раньше")
+	schemas.Describe("MovieNight", "deadline", "Когда голосование завершится само")
+	schemas.Describe("MovieNight", "closedAt", "Когда голосование завершилось; нет, пока оно идёт")
+	schemas.Describe("MovieNight", "winner", "Фильм-победитель после завершения; нет, если никто не проголосовал")
+	schemas.Enum("NightInput", "method", []string{models.VotingApproval, models.VotingRanked})
+	schemas.Describe("NightInput", "movieIds", fmt.Sprintf("От %d до %d фильмов каталога", movienight.MinMovies, movienight.MaxMovies))
+	schemas.Describe("NightInput", "deadline", fmt.Sprintf("Срок голосования, не позже чем через %d дней", int(movienight.MaxDuration.Hours()/24)))
+	schemas.Describe("BallotInput", "name", fmt.Sprintf("Имя, под которым участника видят остальные, до %d символов", movienight.MaxNameLength))
+	schemas.Describe("BallotInput", "choices", "approval — все подходящие фильмы; ranked — фильмы от самого желанного")
+	schemas.Describe("NightResults", "participants", "Имена проголосовавших; безымянные учитываются только в voters")
+	schemas.Describe("NightResults", "tally", "Отметки (approval) или первые предпочтения (ranked), лидеры первыми")
+	schemas.Describe("NightResults", "rounds", "Туры мгновенного второго тура, только для ranked")
+	schemas.Describe("NightResults", "leader", "Фильм, который победил бы, если бы голосование завершилось сейчас")
+	schemas.Enum("MovieRevision", "action", []string{models.RevisionCreate, models.RevisionUpdate, models.RevisionDelete})
+	schemas.Describe("MovieRevision", "revision", "Сквозной номер правки в журнале")
+	schemas.Describe("MovieRevision", "author", "Кто внёс правку; нет, если автор неизвестен")
+	schemas.Describe("MovieRevision", "source", "Чем внесена правка: file — найдена в файле каталога, api — возврат к ревизии, иначе имя утилиты")
+	schemas.Describe("MovieRevision", "revertOf", "Номер правки, к состоянию после которой вернули фильм")
+	schemas.Describe("FieldChange", "old", "Прежнее значение в том виде, как в файле каталога; нет у нового поля")
+	schemas.Describe("FieldChange", "new", "Новое значение; нет у удалённого поля")
+	schemas.Describe("RevertInput", "revision", "Номер правки этого фильма из истории")
+	schemas.Describe("RevertInput", "author", "Кто возвращает фильм; по умолчанию admin")
+	schemas.Describe("Collection", "slug", "Идентификатор в адресе: латиница, цифры и дефисы")
+	schemas.Describe("Collection", "movieIds", "Фильмы подборки в порядке показа")
+	schemas.Describe("Collection", "cover", "Адрес обложки; без неё показывается постер первого фильма")
+	schemas.Describe("CollectionDetails", "movies", "Фильмы подборки, которые есть в каталоге")
+	schemas.Describe("CollectionInput", "slug", "Идентификатор в адресе; при изменении берётся из пути")
+	schemas.Describe("Category", "key", "Ключ категории")
+	schemas.Describe("Category", "name", "Отображаемое название")
+	schemas.Describe("Category", "count", "Количество фильмов в категории")
+	schemas.Describe("SimilarMovie", "score", "Оценка сходства от 0 до 1: описание, метки, люди, категория и год")
+	schemas.Describe("Tag", "key", "Метка, например time-travel")
+	schemas.Describe("Tag", "name", "Отображаемое название")
+	schemas.Describe("Tag", "count", "Количество фильмов с меткой")
+	schemas.Describe("Meta", "count", "Количество элементов в data")
+	schemas.Describe("Meta", "catalogVersion", "Версия каталога, из которой получены данные")
+	schemas.Enum("APIError", "code", api.Codes())
+	schemas.Describe("APIError", "message", "Сообщение на языке из Accept-Language (ru, en)")
+
+	// envelope описывает успешный ответ API v1 с данными по схеме data
+	envelope := func(data *openapi.Schema) *openapi.Schema {
+		return &openapi.Schema{
+			Type:       "object",
+			Properties: map[string]*openapi.Schema{"data": data, "meta": meta},
+			Required:   []string{"data"},
+		}
+	}
+	// v1Responses дополняет ответы API v1 общими ошибками; JSON и YAML выбираются по Accept
+	v1Responses := func(responses map[string]openapi.Response) map[string]openapi.Response {
+		for code, response := range responses {
+			if media, ok := response.Content["application/json"]; ok {
+				response.Content["application/yaml"] = media
+				responses[code] = response
+			}
+		}
+		responses["406"] = openapi.JSON("Запрошенный формат ответа не поддерживается", errorEnvelope)
+		responses["429"] = openapi.Response{
+			Description: "Превышен лимит запросов",
+			Headers: map[string]openapi.Header{
+				"Retry-After": {Description: "Через сколько секунд повторить запрос", Schema: &openapi.Schema{Type: "integer"}},
+			},
+			Content: map[string]openapi.MediaType{"application/json": {Schema: errorEnvelope}},
+		}
+		return responses
+	}
+	acceptLanguage := openapi.Parameter{
+		Name:        "Accept-Language",
+		In:          "header",
+		Description: "Язык сообщений об ошибках, названий и описаний: ru (по умолчанию) или en",
+		Schema:      openapi.String(),
+	}
+	randomParams := []openapi.Parameter{
+		{Name: "category", In: "query", Description: "Ключ категории", Schema: openapi.String()},
+		{Name: "yearFrom", In: "query", Description: "Год выпуска не раньше", Schema: openapi.Integer()},
+		{Name: "maxRuntime", In: "query", Description: "Продолжительность не больше, минут", Schema: openapi.Integer()},
+		{
+			Name: "includeUnknownRuntime", In: "query",
+			Description: "Выбирать ли при maxRuntime фильмы, продолжительность которых неизвестна; по умолчанию true, " +
+				"потому что продолжительность указана не у всех фильмов каталога",
+			Schema: &openapi.Schema{Type: "boolean"},
+		},
+		{Name: "excludeWatched", In: "query", Description: "true — не предлагать фильмы, которые зритель из cookie visitor уже оценил", Schema: &openapi.Schema{Type: "boolean"}},
+	}
+	randomDescription := "Случайный фильм, подходящий под условия. Вероятность выбора пропорциональна квадрату " +
+		"сглаженной средней оценки зрителей, поэтому высоко оценённые фильмы выпадают чаще. " +
+		"Фильмы без продолжительности считаются подходящими под maxRuntime, пока не передан includeUnknownRuntime=false."
+	recommendationsLimitParam := openapi.Parameter{
+		Name:        "limit",
+		In:          "query",
+		Description: fmt.Sprintf("Сколько фильмов вернуть: от 1 до %d, по умолчанию %d", maxRecommendationsLimit, defaultRecommendationsLimit),
+		Schema:      openapi.Integer(),
+	}
+	similarLimitParam := openapi.Parameter{
+		Name:        "limit",
+		In:          "query",
+		Description: fmt.Sprintf("Сколько фильмов вернуть: от 1 до %d, по умолчанию %d", maxSimilarLimit, defaultSimilarLimit),
+		Schema:      openapi.Integer(),
+	}
+
+	providers := []string{}
+	for _, provider := range external.Providers() {
+		providers = append(providers, string(provider))
+	}
+	providerParam := openapi.PathParam("provider", "Внешний сервис")
+	providerParam.Schema = &openapi.Schema{Type: "string", Enum: providers}
+	externalParams := []openapi.Parameter{
+		providerParam,
+		openapi.PathParam("id", "Идентификатор фильма в сервисе, например 1044002 или tt1375666"),
+	}
+
+	// adminOnly описывает изменяющую операцию: нужен токен администратора и действует
+	// строгий лимит частоты запросов
+	adminOnly := func(op openapi.Operation) openapi.Operation {
+		op.Security = []map[string][]string{{"adminToken": {}}}
+		op.Responses["401"] = openapi.JSON("Нет токена администратора или он неверный", errorEnvelope)
+		op.Responses["403"] = openapi.JSON("Изменения отключены: не задан ADMIN_TOKEN", errorEnvelope)
+		return op
+	}
+
+	errorResponses := func(responses map[string]openapi.Response) map[string]openapi.Response {
+		responses["429"] = openapi.Response{
+			Description: "Превышен лимит запросов",
+			Headers: map[string]openapi.Header{
+				"Retry-After": {Description: "Через сколько секунд повторить запрос", Schema: &openapi.Schema{Type: "integer"}},
+			},
+			Content: map[string]openapi.MediaType{"application/json": {Schema: apiError}},
+		}
+		return responses
+	}
+
+	return &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:       "Каталог фильмов",
+			Description: "API каталога фильмов, рекомендованных друзьям.",
+			Version:     "1.0.0",
+		},
+		Paths: map[string]openapi.PathItem{
+			"/api/movies": {
+				"get": {
+					Summary:     "Все фильмы по категориям",
+					Description: "Устарел, используйте /api/v1/movies.",
+					OperationID: "listMovies",
+					Deprecated:  true,
+					Tags:        []string{"movies"},
+					Responses: errorResponses(map[string]openapi.Response{
+						"200": openapi.JSON("Фильмы, сгруппированные по ключам категорий", openapi.MapOf(openapi.ArrayOf(movie))),
+					}),
+				},
+			},
+			"/api/movies/{category}": {
+				"get": {
+					Summary:     "Фильмы категории",
+					Description: "Устарел, используйте /api/v1/categories/{category}/movies.",
+					OperationID: "listMoviesByCategory",
+					Deprecated:  true,
+					Tags:        []string{"movies"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("category", "Ключ категории")},
+					Responses: errorResponses(map[string]openapi.Response{
+						"200": openapi.JSON("Фильмы категории", openapi.ArrayOf(movie)),
+						"404": openapi.JSON("Категория не найдена", apiError),
+					}),
+				},
+			},
+			"/api/movie/{id}": {
+				"get": {
+					Summary:     "Фильм по идентификатору",
+					Description: "Устарел, используйте /api/v1/movies/{id}.",
+					OperationID: "getMovie",
+					Deprecated:  true,
+					Tags:        []string{"movies"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор фильма")},
+					Responses: errorResponses(map[string]openapi.Response{
+						"200": openapi.JSON("Фильм", movie),
+						"404": openapi.JSON("Фильм не найден", apiError),
+					}),
+				},
+			},
+			"/api/movie/{id}/similar": {
+				"get": {
+					Summary:     "Похожие фильмы",
+					Description: "Те же данные, что /api/v1/movies/{id}/similar, но без конверта v1.",
+					OperationID: "getSimilarMovies",
+					Tags:        []string{"movies"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор фильма"), similarLimitParam},
+					Responses: errorResponses(map[string]openapi.Response{
+						"200": openapi.JSON("Похожие фильмы, самые похожие первыми", openapi.ArrayOf(similarMovie)),
+						"400": openapi.JSON("Некорректный limit", apiError),
+						"404": openapi.JSON("Фильм не найден", apiError),
+					}),
+				},
+			},
+			"/api/movie/{id}/history": {
+				"get": {
+					Summary:     "История правок фильма",
+					Description: "Те же данные, что /api/v1/movies/{id}/history, но без конверта v1.",
+					OperationID: "getMovieHistory",
+					Tags:        []string{"movies"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор фильма")},
+					Responses: errorResponses(map[string]openapi.Response{
+						"200": openapi.JSON("Правки фильма от новых к старым", openapi.ArrayOf(movieRevision)),
+						"404": openapi.JSON("Фильм не найден", apiError),
+					}),
+				},
+			},
+			"/api/movie/by-external/{provider}/{id}": {
+				"get": {
+					Summary:     "Фильм по идентификатору во внешнем сервисе",
+					Description: "Устарел, используйте /api/v1/movies/by-external/{provider}/{id}.",
+					OperationID: "getMovieByExternalID",
+					Deprecated:  true,
+					Tags:        []string{"movies"},
+					Parameters:  externalParams,
+					Responses: errorResponses(map[string]openapi.Response{
+						"200": openapi.JSON("Фильм", movie),
+						"400": openapi.JSON("Неизвестный сервис", apiError),
+						"404": openapi.JSON("Фильм не найден", apiError),
+					}),
+				},
+			},
+			"/api/person/{id}": {
+				"get": {
+					Summary:     "Человек и его фильмы",
+					Description: "Те же данные, что /api/v1/people/{id}, но без конверта v1.",
+					OperationID: "getPerson",
+					Tags:        []string{"people"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор человека, например christopher-nolan")},
+					Responses: errorResponses(map[string]openapi.Response{
+						"200": openapi.JSON("Человек и фильмы каталога с его участием", personDetails),
+						"404": openapi.JSON("Человек не найден", apiError),
+					}),
+				},
+			},
+			"/api/categories": {
+				"get": {
+					Summary:     "Список категорий",
+					Description: "Устарел, используйте /api/v1/categories.",
+					OperationID: "listCategories",
+					Deprecated:  true,
+					Tags:        []string{"categories"},
+					Responses: errorResponses(map[string]openapi.Response{
+						"200": openapi.JSON("Категории с количеством фильмов", openapi.ArrayOf(category)),
+					}),
+				},
+			},
+			"/api/random": {
+				"get": {
+					Summary:     "Что посмотреть: случайный фильм",
+					Description: "Те же данные, что /api/v1/movies/random, но без конверта v1. " + randomDescription,
+					OperationID: "getRandomMovie",
+					Tags:        []string{"movies"},
+					Parameters:  randomParams,
+					Responses: errorResponses(map[string]openapi.Response{
+						"200": openapi.JSON("Выбранный фильм", movie),
+						"400": openapi.JSON("Некорректный параметр", apiError),
+						"404": openapi.JSON("Нет подходящих фильмов", apiError),
+					}),
+				},
+			},
+			"/api/tags": {
+				"get": {
+					Summary:     "Список меток",
+					Description: "Те же данные, что /api/v1/tags, но без конверта v1.",
+					OperationID: "listTags",
+					Tags:        []string{"movies"},
+					Responses: errorResponses(map[string]openapi.Response{
+						"200": openapi.JSON("Метки с количеством фильмов, частые первыми", openapi.ArrayOf(tag)),
+					}),
+				},
+			},
+			"/api/me/recommendations": {
+				"get": {
+					Summary:     "Персональные рекомендации",
+					Description: "Те же данные, что /api/v1/me/recommendations, но без конверта v1.",
+					OperationID: "getRecommendations",
+					Tags:        []string{"movies"},
+					Parameters:  []openapi.Parameter{recommendationsLimitParam},
+					Responses: errorResponses(map[string]openapi.Response{
+						"200": openapi.JSON("Рекомендации", openapi.ArrayOf(recommendation)),
+						"400": openapi.JSON("Некорректный limit", apiError),
+					}),
+				},
+			},
+			"/api/v1/movies": {
+				"get": {
+					Summary:     "Список фильмов",
+					OperationID: "v1ListMovies",
+					Tags:        []string{"v1"},
+					Description: "Все фильмы или фильмы, подходящие под фильтры. Фильтры объединяются по «и».",
+					Parameters: []openapi.Parameter{
+						{Name: "category", In: "query", Description: "Ключ категории для фильтрации", Schema: openapi.String()},
+						{Name: "q", In: "query", Description: "Поиск по названиям, описаниям и именам; совпадения в названии идут первыми", Schema: openapi.String()},
+						{Name: "director", In: "query", Description: "Часть имени режиссёра, например Нолан", Schema: openapi.String()},
+						{Name: "actor", In: "query", Description: "Часть имени актёра", Schema: openapi.String()},
+						{Name: "country", In: "query", Description: "Страна производства, например США", Schema: openapi.String()},
+						{Name: "yearFrom", In: "query", Description: "Год выпуска не раньше", Schema: openapi.Integer()},
+						{Name: "yearTo", In: "query", Description: "Год выпуска не позже", Schema: openapi.Integer()},
+						{Name: "runtimeFrom", In: "query", Description: "Продолжительность не меньше, минут", Schema: openapi.Integer()},
+						{Name: "runtimeTo", In: "query", Description: "Продолжительность не больше, минут", Schema: openapi.Integer()},
+						{Name: "maxAge", In: "query", Description: "Возраст зрителя: фильмы с ограничением не старше maxAge+", Schema: openapi.Integer()},
+						{Name: "tags", In: "query", Description: "Метки через запятую или повтором параметра, например time-travel,space", Schema: openapi.String()},
+						{Name: "tagMode", In: "query", Description: "all — нужны все метки из tags (по умолчанию), any — хотя бы одна", Schema: &openapi.Schema{Type: "string", Enum: []string{"all", "any"}}},
+						acceptLanguage,
+					},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Фильмы", envelope(openapi.ArrayOf(movie))),
+						"400": openapi.JSON("Некорректный числовой параметр или tagMode", errorEnvelope),
+						"404": openapi.JSON("Категория не найдена", errorEnvelope),
+					}),
+				},
+			},
+			"/api/v1/movies/random": {
+				"get": {
+					Summary:     "Что посмотреть: случайный фильм",
+					Description: randomDescription,
+					OperationID: "v1GetRandomMovie",
+					Tags:        []string{"v1"},
+					Parameters:  append(append([]openapi.Parameter(nil), randomParams...), acceptLanguage),
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Выбранный фильм", envelope(movie)),
+						"400": openapi.JSON("Некорректный параметр", errorEnvelope),
+						"404": openapi.JSON("Категория не найдена или нет подходящих фильмов", errorEnvelope),
+					}),
+				},
+			},
+			"/api/v1/movies/{id}": {
+				"get": {
+					Summary:     "Фильм по идентификатору",
+					OperationID: "v1GetMovie",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор фильма"), acceptLanguage},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Фильм", envelope(movie)),
+						"404": openapi.JSON("Фильм не найден", errorEnvelope),
+					}),
+				},
+			},
+			"/api/v1/movies/{id}/similar": {
+				"get": {
+					Summary:     "Похожие фильмы",
+					Description: "Фильмы ранжируются по близости описаний (TF-IDF по основам русских слов), общим меткам, людям, категории и году выпуска.",
+					OperationID: "v1GetSimilarMovies",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор фильма"), similarLimitParam, acceptLanguage},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Похожие фильмы, самые похожие первыми", envelope(openapi.ArrayOf(similarMovie))),
+						"400": openapi.JSON("Некорректный limit", errorEnvelope),
+						"404": openapi.JSON("Фильм не найден", errorEnvelope),
+					}),
+				},
+			},
+			"/api/v1/movies/{id}/history": {
+				"get": {
+					Summary: "История правок фильма",
+					Description: "Все изменения фильма из журнала правок: кто, когда и чем изменил и какие поля. " +
+						"Удалённые фильмы тоже есть в истории.",
+					OperationID: "v1GetMovieHistory",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор фильма"), acceptLanguage},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Правки фильма от новых к старым", envelope(openapi.ArrayOf(movieRevision))),
+						"404": openapi.JSON("Фильма нет ни в каталоге, ни в журнале", errorEnvelope),
+					}),
+				},
+			},
+			"/api/v1/movies/{id}/revert": {
+				"post": adminOnly(openapi.Operation{
+					Summary: "Вернуть фильм к ревизии",
+					Description: "Приводит фильм в файле каталога к состоянию после указанной правки и записывает возврат " +
+						"в журнал новой правкой. Если после той правки фильм был удалён, он удаляется.",
+					OperationID: "v1RevertMovie",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор фильма")},
+					RequestBody: openapi.JSONBody("Номер правки", revertInput),
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Записанная правка; пустой список, если фильм уже в этом состоянии", envelope(openapi.ArrayOf(movieRevision))),
+						"400": openapi.JSON("Некорректное тело запроса", errorEnvelope),
+						"404": openapi.JSON("У фильма нет такой правки", errorEnvelope),
+						"422": openapi.JSON("Каталог после возврата не прошёл проверку", errorEnvelope),
+					}),
+				}),
+			},
+			"/api/v1/movies/by-external/{provider}/{id}": {
+				"get": {
+					Summary:     "Фильм по идентификатору во внешнем сервисе",
+					Description: "Например, /api/v1/movies/by-external/kinopoisk/1044002.",
+					OperationID: "v1GetMovieByExternalID",
+					Tags:        []string{"v1"},
+					Parameters:  append(externalParams, acceptLanguage),
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Фильм", envelope(movie)),
+						"400": openapi.JSON("Неизвестный сервис", errorEnvelope),
+						"404": openapi.JSON("Фильм не найден", errorEnvelope),
+					}),
+				},
+			},
+			"/api/v1/people/{id}": {
+				"get": {
+					Summary:     "Человек и его фильмы",
+					Description: "Режиссёр или актёр из people.json и фильмы каталога, где он режиссёр или в ролях.",
+					OperationID: "v1GetPerson",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор человека, например christopher-nolan"), acceptLanguage},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Человек и фильмы каталога с его участием", envelope(personDetails)),
+						"404": openapi.JSON("Человек не найден", errorEnvelope),
+					}),
+				},
+			},
+			"/api/v1/me/ratings": {
+				"get": {
+					Summary:     "Оценки зрителя",
+					Description: "Зритель определяется по cookie visitor; без неё список пуст.",
+					OperationID: "v1ListMyRatings",
+					Tags:        []string{"v1"},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Оценки, последние изменённые первыми", envelope(openapi.ArrayOf(userRating))),
+					}),
+				},
+			},
+			"/api/v1/me/ratings/{id}": {
+				"put": {
+					Summary:     "Оценить фильм",
+					Description: "Ставит или меняет оценку. Если у зрителя нет cookie visitor, она выдаётся в ответе.",
+					OperationID: "v1RateMovie",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор фильма")},
+					RequestBody: openapi.JSONBody("Оценка", ratingInput),
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Сохранённая оценка", envelope(userRating)),
+						"400": openapi.JSON("Некорректное тело запроса", errorEnvelope),
+						"404": openapi.JSON("Фильм не найден", errorEnvelope),
+						"422": openapi.JSON("Оценка вне допустимого диапазона", errorEnvelope),
+					}),
+				},
+				"delete": {
+					Summary:     "Убрать оценку фильма",
+					OperationID: "v1UnrateMovie",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор фильма")},
+					Responses: v1Responses(map[string]openapi.Response{
+						"204": {Description: "Оценки больше нет"},
+					}),
+				},
+			},
+			"/api/v1/me/watchlist": {
+				"get": {
+					Summary:     "Список «посмотреть позже»",
+					Description: "Зритель определяется по cookie visitor; без неё список пуст.",
+					OperationID: "v1ListMyWatchlist",
+					Tags:        []string{"v1"},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Отложенные фильмы, последние добавленные первыми", envelope(openapi.ArrayOf(watchlistItem))),
+					}),
+				},
+			},
+			"/api/v1/me/watchlist/{id}": {
+				"put": {
+					Summary:     "Отложить фильм на потом",
+					Description: "Если у зрителя нет cookie visitor, она выдаётся в ответе.",
+					OperationID: "v1AddToWatchlist",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор фильма")},
+					Responses: v1Responses(map[string]openapi.Response{
+						"204": {Description: "Фильм в списке"},
+						"404": openapi.JSON("Фильм не найден", errorEnvelope),
+					}),
+				},
+				"delete": {
+					Summary:     "Убрать фильм из списка «посмотреть позже»",
+					OperationID: "v1RemoveFromWatchlist",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор фильма")},
+					Responses: v1Responses(map[string]openapi.Response{
+						"204": {Description: "Фильма больше нет в списке"},
+					}),
+				},
+			},
+			"/api/v1/me/recommendations": {
+				"get": {
+					Summary: "Персональные рекомендации",
+					Description: "Сначала фильмы, которые высоко оценили зрители с похожими оценками (нужно не меньше трёх своих оценок), " +
+						"затем похожие по содержанию на понравившиеся и отложенные, затем самые высоко оценённые. " +
+						"Оценённые и отложенные фильмы не рекомендуются.",
+					OperationID: "v1GetRecommendations",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{recommendationsLimitParam, acceptLanguage},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Рекомендации", envelope(openapi.ArrayOf(recommendation))),
+						"400": openapi.JSON("Некорректный limit", errorEnvelope),
+					}),
+				},
+			},
+			"/api/v1/nights": {
+				"post": {
+					Summary: "Начать голосование за фильм на вечер",
+					Description: "Создатель получает cookie visitor, по которой может завершить голосование досрочно. " +
+						"Ссылкой на страницу /night/{id} делятся с участниками.",
+					OperationID: "v1CreateNight",
+					Tags:        []string{"v1"},
+					RequestBody: openapi.JSONBody("Голосование", nightInput),
+					Responses: v1Responses(map[string]openapi.Response{
+						"201": openapi.JSON("Созданное голосование", envelope(movieNight)),
+						"400": openapi.JSON("Некорректное тело запроса", errorEnvelope),
+						"422": openapi.JSON("Голосование не прошло проверку или фильмов нет в каталоге", errorEnvelope),
+					}),
+				},
+			},
+			"/api/v1/nights/{id}": {
+				"get": {
+					Summary:     "Итоги голосования",
+					OperationID: "v1GetNight",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор голосования")},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Голосование с текущими итогами", envelope(nightResults)),
+						"404": openapi.JSON("Голосование не найдено", errorEnvelope),
+					}),
+				},
+			},
+			"/api/v1/nights/{id}/ballot": {
+				"put": {
+					Summary:     "Проголосовать",
+					Description: "Бюллетень участника из cookie visitor заменяет его прежний; cookie выдаётся, если её нет.",
+					OperationID: "v1VoteNight",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор голосования")},
+					RequestBody: openapi.JSONBody("Бюллетень", ballotInput),
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Итоги с учётом голоса", envelope(nightResults)),
+						"400": openapi.JSON("Некорректное тело запроса", errorEnvelope),
+						"404": openapi.JSON("Голосование не найдено", errorEnvelope),
+						"409": openapi.JSON("Голосование завершено", errorEnvelope),
+						"422": openapi.JSON("Бюллетень не прошёл проверку", errorEnvelope),
+					}),
+				},
+			},
+			"/api/v1/nights/{id}/close": {
+				"post": {
+					Summary:     "Завершить голосование досрочно",
+					Description: "Доступно только создателю голосования (по cookie visitor).",
+					OperationID: "v1CloseNight",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор голосования")},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Итоги с победителем", envelope(nightResults)),
+						"403": openapi.JSON("Голосование создал другой участник", errorEnvelope),
+						"404": openapi.JSON("Голосование не найдено", errorEnvelope),
+						"409": openapi.JSON("Голосование уже завершено", errorEnvelope),
+					}),
+				},
+			},
+			"/api/v1/nights/{id}/events": {
+				"get": {
+					Summary: "Поток итогов голосования",
+					Description: "Server-Sent Events: сразу после подключения — текущие итоги, затем night.updated " +
+						"после каждого голоса и night.closed с победителем, после которого поток закрывается. " +
+						"Данные событий — NightResults.",
+					OperationID: "v1StreamNight",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("id", "Идентификатор голосования")},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": {
+							Description: "Поток событий",
+							Content:     map[string]openapi.MediaType{"text/event-stream": {Schema: openapi.String()}},
+						},
+						"404": openapi.JSON("Голосование не найдено", errorEnvelope),
+					}),
+				},
+			},
+			"/api/v1/collections": {
+				"get": {
+					Summary:     "Список подборок",
+					OperationID: "v1ListCollections",
+					Tags:        []string{"v1"},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Подборки в порядке создания", envelope(openapi.ArrayOf(collection))),
+					}),
+				},
+				"post": adminOnly(openapi.Operation{
+					Summary:     "Создать подборку",
+					OperationID: "v1CreateCollection",
+					Tags:        []string{"v1"},
+					RequestBody: openapi.JSONBody("Новая подборка", collectionInput),
+					Responses: v1Responses(map[string]openapi.Response{
+						"201": openapi.JSON("Созданная подборка", envelope(collection)),
+						"400": openapi.JSON("Некорректное тело запроса", errorEnvelope),
+						"409": openapi.JSON("Подборка с таким slug уже есть", errorEnvelope),
+						"422": openapi.JSON("Подборка не прошла проверку или фильмов нет в каталоге", errorEnvelope),
+					}),
+				}),
+			},
+			"/api/v1/collections/{slug}": {
+				"get": {
+					Summary:     "Подборка с фильмами",
+					OperationID: "v1GetCollection",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("slug", "Идентификатор подборки"), acceptLanguage},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Подборка", envelope(collectionDetails)),
+						"404": openapi.JSON("Подборка не найдена", errorEnvelope),
+					}),
+				},
+				"put": adminOnly(openapi.Operation{
+					Summary:     "Изменить подборку",
+					Description: "Заменяет название, описание, список фильмов и обложку целиком.",
+					OperationID: "v1UpdateCollection",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("slug", "Идентификатор подборки")},
+					RequestBody: openapi.JSONBody("Новое содержимое подборки", collectionInput),
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Изменённая подборка", envelope(collection)),
+						"400": openapi.JSON("Некорректное тело запроса", errorEnvelope),
+						"404": openapi.JSON("Подборка не найдена", errorEnvelope),
+						"422": openapi.JSON("Подборка не прошла проверку или фильмов нет в каталоге", errorEnvelope),
+					}),
+				}),
+				"delete": adminOnly(openapi.Operation{
+					Summary:     "Удалить подборку",
+					OperationID: "v1DeleteCollection",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("slug", "Идентификатор подборки")},
+					Responses: v1Responses(map[string]openapi.Response{
+						"204": {Description: "Подборка удалена"},
+						"404": openapi.JSON("Подборка не найдена", errorEnvelope),
+					}),
+				}),
+			},
+			"/api/v1/categories": {
+				"get": {
+					Summary:     "Список категорий",
+					OperationID: "v1ListCategories",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{acceptLanguage},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Категории с количеством фильмов", envelope(openapi.ArrayOf(category))),
+					}),
+				},
+			},
+			"/api/v1/tags": {
+				"get": {
+					Summary:     "Список меток",
+					OperationID: "v1ListTags",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{acceptLanguage},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Метки с количеством фильмов, частые первыми", envelope(openapi.ArrayOf(tag))),
+					}),
+				},
+			},
+			"/api/v1/categories/{category}/movies": {
+				"get": {
+					Summary:     "Фильмы категории",
+					OperationID: "v1ListCategoryMovies",
+					Tags:        []string{"v1"},
+					Parameters:  []openapi.Parameter{openapi.PathParam("category", "Ключ категории"), acceptLanguage},
+					Responses: v1Responses(map[string]openapi.Response{
+						"200": openapi.JSON("Фильмы категории", envelope(openapi.ArrayOf(movie))),
+						"404": openapi.JSON("Категория не найдена", errorEnvelope),
+					}),
+				},
+			},
+			"/api/events": {
+				"get": {
+					Summary: "Поток изменений каталога",
+					Description: "Server-Sent Events с типами movie.added и movie.updated (данные — фильм), " +
+						"movie.removed (id и category) и catalog.reloaded (version и count).",
+					OperationID: "streamEvents",
+					Tags:        []string{"events"},
+					Responses: errorResponses(map[string]openapi.Response{
+						"200": {
+							Description: "Поток событий",
+							Content:     map[string]openapi.MediaType{"text/event-stream": {Schema: openapi.String()}},
+						},
+					}),
+				},
+			},
+			"/api/openapi.json": {
+				"get": {
+					Summary:     "Эта спецификация OpenAPI",
+					OperationID: "getOpenAPI",
+					Tags:        []string{"docs"},
+					Responses: map[string]openapi.Response{
+						"200": openapi.JSON("Документ OpenAPI 3", &openapi.Schema{Type: "object"}),
+					},
+				},
+			},
+			"/api/docs": {
+				"get": {
+					Summary:     "Страница с документацией API",
+					OperationID: "getAPIDocs",
+					Tags:        []string{"docs"},
+					Responses: map[string]openapi.Response{
+						"200": {
+							Description: "HTML-страница просмотра спецификации",
+							Content:     map[string]openapi.MediaType{"text/html": {Schema: openapi.String()}},
+						},
+					},
+				},
+			},
+		},
+		Components: openapi.Components{
+			Schemas: schemas.Schemas(),
+			SecuritySchemes: map[string]openapi.SecurityScheme{
+				"adminToken": {Type: "http", Scheme: "bearer", Description: "Значение ADMIN_TOKEN сервера"},
+			},
+		},
+	}
+}
+
+// Спецификация API, собранная при запуске
+var apiSpec = buildAPISpec()
+
+// Обработчик API, отдающий спецификацию OpenAPI
+func handleAPIOpenAPI(c *gin.Context) {
+	c.JSON(http.StatusOK, apiSpec)
+}
+
+// Обработчик страницы просмотра документации API
+func handleAPIDocs(c *gin.Context) {
+	renderTemplate(c, "apidocs.html", map[string]interface{}{
+		"title":   "API каталога фильмов",
+		"specURL": "/api/openapi.json",
+	})
+}
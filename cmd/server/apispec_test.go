@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"movie-catalog/internal/catalog"
+	"movie-catalog/internal/config"
+)
+
+var routeParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// TestOpenAPIMatchesRoutes проверяет, что спецификация описывает ровно те маршруты /api,
+// которые зарегистрированы в роутере
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	movieCatalog = catalog.New("")
+	router, err := setupRouter(slog.New(slog.NewTextHandler(io.Discard, nil)), config.Load())
+	if err != nil {
+		t.Fatalf("setupRouter: %v", err)
+	}
+
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/") {
+			continue
+		}
+		path := routeParam.ReplaceAllString(route.Path, "{$1}")
+		registered[strings.ToLower(route.Method)+" "+path] = true
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range buildAPISpec().Paths {
+		for method := range item {
+			documented[method+" "+path] = true
+		}
+	}
+
+	for _, op := range sortedKeys(registered) {
+		if !documented[op] {
+			t.Errorf("маршрут %s не описан в спецификации OpenAPI", op)
+		}
+	}
+	for _, op := range sortedKeys(documented) {
+		if !registered[op] {
+			t.Errorf("операция %s описана в спецификации, но не зарегистрирована в роутере", op)
+		}
+	}
+}
+
+// TestOpenAPIRefsResolve проверяет, что все ссылки $ref указывают на существующие схемы
+func TestOpenAPIRefsResolve(t *testing.T) {
+	spec := buildAPISpec()
+	data, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	refs := regexp.MustCompile(`"\$ref":"#/components/schemas/([^"]+)"`).FindAllStringSubmatch(string(data), -1)
+	if len(refs) == 0 {
+		t.Fatal("в спецификации нет ссылок на схемы")
+	}
+	for _, ref := range refs {
+		if _, ok := spec.Components.Schemas[ref[1]]; !ok {
+			t.Errorf("схема %q не найдена в components", ref[1])
+		}
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/api"
+	"movie-catalog/internal/collections"
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/middleware"
+	"movie-catalog/internal/models"
+)
+
+// Подборки фильмов
+var collectionStore *collections.Store
+
+// Токен администратора для изменяющих запросов API из ADMIN_TOKEN
+var adminToken string
+
+// requireAdmin пропускает только запросы с заголовком Authorization: Bearer <ADMIN_TOKEN>.
+// Без настроенного токена изменения через API отключены.
+func requireAdmin(c *gin.Context) {
+	if adminToken == "" {
+		api.Fail(c, http.StatusForbidden, api.CodeWriteDisabled)
+		c.Abort()
+		return
+	}
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+		c.Header("WWW-Authenticate", `Bearer realm="movie-catalog"`)
+		api.Fail(c, http.StatusUnauthorized, api.CodeUnauthorized)
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// collectionPath возвращает адрес страницы подборки
+func collectionPath(slug string) string {
+	return "/collection/" + url.PathEscape(slug)
+}
+
+// collectionDetails дополняет подборку фильмами на языке locale.
+// Фильмы, которых больше нет в каталоге, пропускаются.
+func collectionDetails(collection models.Collection, locale string) models.CollectionDetails {
+	movies := make([]models.Movie, 0, len(collection.MovieIDs))
+	for _, id := range collection.MovieIDs {
+		if movie, ok := movieCatalog.Movie(id); ok {
+			movies = append(movies, movie.Localized(locale))
+		}
+	}
+	return models.CollectionDetails{Collection: collection, Movies: movies}
+}
+
+// collectionCover возвращает обложку подборки или постер её первого фильма
+func collectionCover(details models.CollectionDetails) string {
+	if details.Cover != "" {
+		return details.Cover
+	}
+	for _, movie := range details.Movies {
+		if movie.ImagePath != "" {
+			return movie.ImagePath
+		}
+	}
+	return ""
+}
+
+// collectionCard — подборка в ленте на главной странице
+type collectionCard struct {
+	Title string
+	URL   string
+	Cover string
+	Count int
+}
+
+// collectionCards собирает карточки подборок для главной страницы
+func collectionCards(locale string) []collectionCard {
+	var cards []collectionCard
+	for _, collection := range collectionStore.List() {
+		details := collectionDetails(collection, locale)
+		cards = append(cards, collectionCard{
+			Title: collection.Title,
+			URL:   localePath(locale, collectionPath(collection.Slug)),
+			Cover: collectionCover(details),
+			Count: len(details.Movies),
+		})
+	}
+	return cards
+}
+
+// Обработчик страницы подборки
+func handleCollection(c *gin.Context) {
+	locale := middleware.Locale(c)
+	collection, ok := collectionStore.Get(c.Param("slug"))
+	if !ok {
+		c.String(http.StatusNotFound, i18n.T(locale, "error.collection_not_found"))
+		return
+	}
+	details := collectionDetails(collection, locale)
+
+	meta := pageMeta{
+		Title:       collection.Title,
+		Description: collection.Description,
+		URL:         baseURL(c) + localePath(locale, collectionPath(collection.Slug)),
+	}
+	if cover := collectionCover(details); cover != "" {
+		meta.Image = absoluteURL(c, cover)
+	}
+
+	renderPage(c, map[string]interface{}{
+		"title":      collection.Title,
+		"page":       "collection",
+		"collection": details,
+		"meta":       meta,
+	})
+}
+
+// collectionInput — тело запросов создания и изменения подборки
+type collectionInput struct {
+	Slug        string   `json:"slug"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	MovieIDs    []string `json:"movieIds,omitempty"`
+	Cover       string   `json:"cover,omitempty"`
+}
+
+// bindCollection разбирает тело запроса и проверяет, что все фильмы есть в каталоге.
+// При ошибке отвечает сам и возвращает false.
+func bindCollection(c *gin.Context) (models.Collection, bool) {
+	var input collectionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		api.FailDetails(c, http.StatusBadRequest, api.CodeBadRequest, err.Error())
+		return models.Collection{}, false
+	}
+
+	var unknown []string
+	for _, id := range input.MovieIDs {
+		if _, ok := movieCatalog.Movie(id); !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		api.FailDetails(c, http.StatusUnprocessableEntity, api.CodeValidationFailed, "нет в каталоге: "+strings.Join(unknown, ", "))
+		return models.Collection{}, false
+	}
+
+	return models.Collection{
+		Slug:        strings.TrimSpace(input.Slug),
+		Title:       strings.TrimSpace(input.Title),
+		Description: strings.TrimSpace(input.Description),
+		MovieIDs:    input.MovieIDs,
+		Cover:       strings.TrimSpace(input.Cover),
+	}, true
+}
+
+// failCollection отвечает ошибкой хранилища подборок
+func failCollection(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, collections.ErrNotFound):
+		api.Fail(c, http.StatusNotFound, api.CodeCollectionNotFound)
+	case errors.Is(err, collections.ErrExists):
+		api.Fail(c, http.StatusConflict, api.CodeCollectionExists)
+	case errors.Is(err, collections.ErrInvalid):
+		api.FailDetails(c, http.StatusUnprocessableEntity, api.CodeValidationFailed, strings.ReplaceAll(err.Error(), "\n", "; "))
+	default:
+		c.Error(err)
+		api.Fail(c, http.StatusInternalServerError, api.CodeInternal)
+	}
+}
+
+// Обработчик API v1 для списка подборок
+func handleV1Collections(c *gin.Context) {
+	list := collectionStore.List()
+	if list == nil {
+		list = []models.Collection{}
+	}
+	api.OK(c, list, listMeta(len(list)))
+}
+
+// Обработчик API v1 для подборки с её фильмами
+func handleV1Collection(c *gin.Context) {
+	collection, ok := collectionStore.Get(c.Param("slug"))
+	if !ok {
+		api.Fail(c, http.StatusNotFound, api.CodeCollectionNotFound)
+		return
+	}
+	api.OK(c, collectionDetails(collection, api.Locale(c)), itemMeta())
+}
+
+// Обработчик API v1 для создания подборки
+func handleV1CreateCollection(c *gin.Context) {
+	collection, ok := bindCollection(c)
+	if !ok {
+		return
+	}
+	created, err := collectionStore.Create(collection)
+	if err != nil {
+		failCollection(c, err)
+		return
+	}
+	c.Header("Location", apiV1Prefix+"/collections/"+url.PathEscape(created.Slug))
+	api.Respond(c, http.StatusCreated, api.Response{Data: created, Meta: itemMeta()})
+}
+
+// Обработчик API v1 для изменения подборки; slug в теле игнорируется
+func handleV1UpdateCollection(c *gin.Context) {
+	collection, ok := bindCollection(c)
+	if !ok {
+		return
+	}
+	updated, err := collectionStore.Update(c.Param("slug"), collection)
+	if err != nil {
+		failCollection(c, err)
+		return
+	}
+	api.OK(c, updated, itemMeta())
+}
+
+// Обработчик API v1 для удаления подборки
+func handleV1DeleteCollection(c *gin.Context) {
+	if err := collectionStore.Delete(c.Param("slug")); err != nil {
+		failCollection(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/catalog"
+	"movie-catalog/internal/events"
+)
+
+// Интервал комментариев-пингов, чтобы прокси не закрывали простаивающее соединение
+const eventsKeepAlive = 30 * time.Second
+
+// Брокер событий каталога для подписчиков /api/events
+var eventBroker = events.NewBroker()
+
+// catalogReloadedEvent — данные события catalog.reloaded
+type catalogReloadedEvent struct {
+	Version string `json:"version"`
+	Count   int    `json:"count"`
+}
+
+// movieRemovedEvent — данные события movie.removed
+type movieRemovedEvent struct {
+	ID       string `json:"id"`
+	Category string `json:"category"`
+}
+
+// publishCatalogDiff рассылает подписчикам изменения каталога после перезагрузки
+func publishCatalogDiff(diff catalog.Diff) {
+	for _, movie := range diff.Added {
+		eventBroker.Publish(events.MovieAdded, movie)
+	}
+	for _, movie := range diff.Updated {
+		eventBroker.Publish(events.MovieUpdated, movie)
+	}
+	for _, movie := range diff.Removed {
+		eventBroker.Publish(events.MovieRemoved, movieRemovedEvent{ID: movie.ID, Category: movie.Category})
+	}
+	eventBroker.Publish(events.CatalogReloaded, catalogReloadedEvent{Version: diff.Version, Count: movieCatalog.Count()})
+}
+
+// Обработчик API, транслирующий изменения каталога по Server-Sent Events
+func handleAPIEvents(c *gin.Context) {
+	stream, unsubscribe := eventBroker.Subscribe()
+	defer unsubscribe()
+	streamEvents(c, stream)
+}
+
+// streamEvents отправляет клиенту сначала события initial, затем события из stream,
+// пока клиент не отключится или канал не закроется
+func streamEvents(c *gin.Context, stream <-chan events.Event, initial ...sse.Event) {
+	header := c.Writer.Header()
+	header.Set("Content-Type", sse.ContentType)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Отключаем буферизацию ответа в nginx
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	for _, event := range initial {
+		c.Render(-1, event)
+	}
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-stream:
+			if !ok {
+				return false
+			}
+			c.Render(-1, sse.Event{
+				Id:    strconv.FormatUint(event.ID, 10),
+				Event: event.Type,
+				Data:  event.Data,
+			})
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/feed"
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/middleware"
+)
+
+// Сколько последних фильмов попадает в ленту
+const feedSize = 50
+
+// Внешний адрес сайта из PUBLIC_URL; пустой — адрес берётся из запроса
+var publicURL string
+
+// Хосты, которые принимаются из заголовка Host, и прокси, которым разрешено
+// сообщать схему в X-Forwarded-Proto. Используются, только когда PUBLIC_URL не задан.
+var (
+	allowedHosts   []string
+	trustedProxies []netip.Prefix
+)
+
+// baseURL возвращает адрес сайта без завершающего слеша для абсолютных ссылок.
+// Без PUBLIC_URL адрес собирается из запроса, но заголовку Host верим только для
+// хостов из ALLOWED_HOSTS, а X-Forwarded-Proto — только от доверенных прокси:
+// иначе клиент мог бы подставить свой домен в ленты и карту сайта, которые кэшируются.
+func baseURL(c *gin.Context) string {
+	if publicURL != "" {
+		return publicURL
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || (c.GetHeader("X-Forwarded-Proto") == "https" && fromTrustedProxy(c)) {
+		scheme = "https"
+	}
+	return scheme + "://" + requestHost(c.Request.Host)
+}
+
+// requestHost возвращает host, если он есть в allowedHosts, иначе первый разрешённый хост
+func requestHost(host string) string {
+	name := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		name = h
+	}
+	name = strings.Trim(name, "[]")
+	for _, allowed := range allowedHosts {
+		if strings.EqualFold(name, allowed) {
+			return host
+		}
+	}
+	if len(allowedHosts) == 0 {
+		return "localhost"
+	}
+	if strings.Contains(allowedHosts[0], ":") {
+		return "[" + allowedHosts[0] + "]"
+	}
+	return allowedHosts[0]
+}
+
+// fromTrustedProxy сообщает, пришёл ли запрос напрямую от доверенного прокси
+func fromTrustedProxy(c *gin.Context) bool {
+	addr, err := netip.ParseAddr(c.RemoteIP())
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseProxies разбирает адреса и подсети из TRUSTED_PROXIES
+func parseProxies(list []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(list))
+	for _, value := range list {
+		if prefix, err := netip.ParsePrefix(value); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("некорректный адрес прокси %q", value)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// absoluteURL дополняет локальный путь адресом сайта; внешние адреса возвращаются как есть
+func absoluteURL(c *gin.Context, ref string) string {
+	if u, err := url.Parse(ref); err == nil && u.IsAbs() {
+		return ref
+	}
+	return baseURL(c) + path.Join("/", ref)
+}
+
+// movieFeed собирает ленту последних добавленных фильмов; при непустом category —
+// только из этой категории
+func movieFeed(c *gin.Context, category, self string) feed.Feed {
+	locale := middleware.Locale(c)
+	base := baseURL(c)
+	f := feed.Feed{
+		Title:       i18n.T(locale, "feed.title"),
+		Description: i18n.T(locale, "feed.description"),
+		Link:        base + localePath(locale, "/movies"),
+		Self:        base + self,
+		Language:    locale,
+	}
+	if category != "" {
+		name := i18n.CategoryName(locale, category)
+		f.Title = i18n.T(locale, "feed.category_title", name)
+		f.Description = i18n.T(locale, "feed.category_description", name)
+		f.Link = base + localePath(locale, "/category/"+url.PathEscape(category))
+	}
+
+	for _, movie := range movieCatalog.Recent(category, feedSize) {
+		movie = movie.Localized(locale)
+		link := base + localePath(locale, moviePath(movie.ID))
+		item := feed.Item{
+			ID:        link,
+			Title:     movieHeading(movie),
+			Link:      link,
+			Summary:   movie.Description,
+			Published: movie.CreatedAt,
+		}
+		if movie.ImagePath != "" {
+			item.Enclosure = &feed.Enclosure{URL: absoluteURL(c, movie.ImagePath), Type: imageType(movie.ImagePath)}
+		}
+		if movie.CreatedAt.After(f.Updated) {
+			f.Updated = movie.CreatedAt
+		}
+		f.Items = append(f.Items, item)
+	}
+	return f
+}
+
+// imageType определяет MIME-тип постера по расширению файла
+func imageType(ref string) string {
+	if u, err := url.Parse(ref); err == nil {
+		if t := mime.TypeByExtension(path.Ext(u.Path)); t != "" {
+			return t
+		}
+	}
+	return "image/jpeg"
+}
+
+// Обработчик ленты RSS последних добавленных фильмов
+func handleFeedRSS(c *gin.Context) {
+	writeFeed(c, movieFeed(c, "", c.Request.URL.Path), "application/rss+xml; charset=utf-8", feed.Feed.RSS)
+}
+
+// Обработчик ленты Atom последних добавленных фильмов
+func handleFeedAtom(c *gin.Context) {
+	writeFeed(c, movieFeed(c, "", c.Request.URL.Path), "application/atom+xml; charset=utf-8", feed.Feed.Atom)
+}
+
+// Обработчик ленты RSS категории
+func handleCategoryFeedRSS(c *gin.Context) {
+	category := c.Param("category")
+	if _, ok := movieCatalog.Category(category); !ok {
+		c.String(http.StatusNotFound, i18n.T(middleware.Locale(c), "error.category_not_found"))
+		return
+	}
+	writeFeed(c, movieFeed(c, category, c.Request.URL.Path), "application/rss+xml; charset=utf-8", feed.Feed.RSS)
+}
+
+func writeFeed(c *gin.Context, f feed.Feed, contentType string, encode func(feed.Feed) ([]byte, error)) {
+	body, err := encode(f)
+	if err != nil {
+		c.Error(err)
+		c.String(http.StatusInternalServerError, "Ошибка формирования ленты")
+		return
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestBaseURL проверяет, что без PUBLIC_URL клиент не может подменить адрес сайта
+func TestBaseURL(t *testing.T) {
+	publicURL = ""
+	allowedHosts = []string{"films.test", "localhost"}
+	proxies, err := parseProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trustedProxies = proxies
+
+	tests := []struct {
+		name   string
+		remote string
+		host   string
+		proto  string
+		want   string
+	}{
+		{"разрешённый хост", "203.0.113.5:1234", "films.test", "", "http://films.test"},
+		{"порт сохраняется", "203.0.113.5:1234", "localhost:8080", "", "http://localhost:8080"},
+		{"чужой хост", "203.0.113.5:1234", "evil.example", "", "http://films.test"},
+		{"схема от доверенного прокси", "10.1.2.3:1234", "films.test", "https", "https://films.test"},
+		{"схема от клиента", "203.0.113.5:1234", "films.test", "https", "http://films.test"},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/feed.rss", nil)
+		c.Request.RemoteAddr = tt.remote
+		c.Request.Host = tt.host
+		if tt.proto != "" {
+			c.Request.Header.Set("X-Forwarded-Proto", tt.proto)
+		}
+		if got := baseURL(c); got != tt.want {
+			t.Errorf("%s: baseURL = %q, ожидался %q", tt.name, got, tt.want)
+		}
+	}
+
+	publicURL = "https://films.example.com"
+	defer func() { publicURL = "" }()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/feed.rss", nil)
+	c.Request.Host = "evil.example"
+	if got := baseURL(c); got != publicURL {
+		t.Errorf("с PUBLIC_URL: baseURL = %q", got)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+
+	"movie-catalog/internal/graph"
+)
+
+// Максимальный размер GraphQL-запроса
+const maxGraphQLRequestSize = 64 << 10
+
+// Ограничения сложности GraphQL-запроса. Типы Movie и Category ссылаются друг на друга
+// (Movie.category и Category.movies), поэтому без ограничения глубины небольшой запрос
+// может заставить сервер собрать огромный ответ.
+const (
+	maxGraphQLDepth  = 6
+	maxGraphQLFields = 200
+)
+
+// GraphQL-схема каталога, строится при запуске сервера
+var graphQLSchema graphql.Schema
+
+// graphQLRequest — тело запроса по протоколу GraphQL over HTTP
+type graphQLRequest struct {
+	Query         string                 `json:"query" form:"query"`
+	OperationName string                 `json:"operationName" form:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Обработчик GraphQL: POST с JSON-телом или GET с параметром query
+func handleGraphQL(c *gin.Context) {
+	var req graphQLRequest
+	if c.Request.Method == http.MethodPost {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxGraphQLRequestSize)
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": "Некорректное тело запроса"}}})
+			return
+		}
+	} else {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": "Некорректный параметр variables"}}})
+				return
+			}
+		}
+	}
+
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": "Не передан запрос query"}}})
+		return
+	}
+
+	// Запрос с синтаксической ошибкой отклонит graphql.Do с подробным сообщением
+	if complexity, err := graph.Analyze(req.Query); err == nil {
+		switch {
+		case complexity.Depth > maxGraphQLDepth:
+			c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{
+				"message": fmt.Sprintf("Слишком глубокий запрос: вложенность %d, допустимо не больше %d", complexity.Depth, maxGraphQLDepth),
+			}}})
+			return
+		case complexity.Fields > maxGraphQLFields:
+			c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{
+				"message": fmt.Sprintf("Слишком сложный запрос: %d полей, допустимо не больше %d", complexity.Fields, maxGraphQLFields),
+			}}})
+			return
+		}
+	}
+
+	// Ответ со значениями Movie.rating зависит от зрителя
+	ctx := c.Request.Context()
+	if userID := visitorID(c); userID != "" {
+		c.Header("Cache-Control", "private")
+		ctx = graph.WithViewer(ctx, userID)
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:         graphQLSchema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+	c.JSON(http.StatusOK, result)
+}
+
+// Обработчик страницы GraphiQL для интерактивных запросов
+func handleGraphiQL(c *gin.Context) {
+	renderTemplate(c, "graphiql.html", map[string]interface{}{
+		"title":    "GraphQL каталога фильмов",
+		"endpoint": "/graphql",
+	})
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/catalog"
+	"movie-catalog/internal/graph"
+	"movie-catalog/internal/ratings"
+)
+
+// graphQLRouter поднимает обработчик GraphQL над каталогом из одного фильма
+func graphQLRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "movies.json")
+	data := `{"drama": [{"id": "inception", "title": "Начало", "year": 2010, "category": "drama", "description": "Сон во сне"}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	movieCatalog = catalog.New(path)
+	if err := movieCatalog.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	var err error
+	ratingStore, err = ratings.Open(filepath.Join(t.TempDir(), "ratings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := graph.NewSchema(movieCatalog, ratingStore)
+	if err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+	graphQLSchema = schema
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/graphql", handleGraphQL)
+	return router
+}
+
+func postGraphQL(router *gin.Engine, query string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(graphQLRequest{Query: query})
+	request := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	request.Header.Set("Content-Type", "application/json")
+	for _, cookie := range cookies {
+		request.AddCookie(cookie)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestGraphQLNestedQuery(t *testing.T) {
+	router := graphQLRouter(t)
+
+	recorder := postGraphQL(router, `{ movie(id: "inception") { title category { key movies { id category { name } } } } }`)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("статус %d: %s", recorder.Code, recorder.Body)
+	}
+	var result struct {
+		Data struct {
+			Movie struct {
+				Title    string
+				Category struct {
+					Key    string
+					Movies []struct{ ID string }
+				}
+			}
+		}
+		Errors []struct{ Message string }
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("ошибки: %v", result.Errors)
+	}
+	movie := result.Data.Movie
+	if movie.Title != "Начало" || movie.Category.Key != "drama" || len(movie.Category.Movies) != 1 || movie.Category.Movies[0].ID != "inception" {
+		t.Errorf("неожиданный ответ: %s", recorder.Body)
+	}
+}
+
+func TestGraphQLRejectsDeepQuery(t *testing.T) {
+	router := graphQLRouter(t)
+
+	deep := `{ movies { category { movies { category { movies { category { name } } } } } } }`
+	recorder := postGraphQL(router, deep)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("статус %d, ожидался 400: %s", recorder.Code, recorder.Body)
+	}
+	if !strings.Contains(recorder.Body.String(), "Слишком глубокий запрос") {
+		t.Errorf("неожиданная ошибка: %s", recorder.Body)
+	}
+
+	// Глубину не спрятать во фрагменты
+	fragments := `{ movies { ...M } }
+		fragment M on Movie { category { movies { ...C } } }
+		fragment C on Movie { category { movies { category { name } } } }`
+	if recorder := postGraphQL(router, fragments); recorder.Code != http.StatusBadRequest {
+		t.Errorf("запрос с фрагментами: статус %d, ожидался 400", recorder.Code)
+	}
+
+	// Интроспекция GraphiQL глубокая, но допускается
+	introspection := `{ __schema { types { name fields { type { ofType { ofType { ofType { ofType { name } } } } } } } } }`
+	if recorder := postGraphQL(router, introspection); recorder.Code != http.StatusOK {
+		t.Errorf("интроспекция: статус %d: %s", recorder.Code, recorder.Body)
+	}
+}
+
+func TestGraphQLMovieRating(t *testing.T) {
+	router := graphQLRouter(t)
+	alice := strings.Repeat("a", 32)
+	if _, err := ratingStore.Rate(alice, "inception", 8); err != nil {
+		t.Fatal(err)
+	}
+
+	rating := func(cookies ...*http.Cookie) interface{} {
+		t.Helper()
+		recorder := postGraphQL(router, `{ movie(id: "inception") { rating } }`, cookies...)
+		var result struct {
+			Data struct {
+				Movie struct{ Rating interface{} }
+			}
+			Errors []struct{ Message string }
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Errors) > 0 {
+			t.Fatalf("ошибки: %v", result.Errors)
+		}
+		return result.Data.Movie.Rating
+	}
+
+	if got := rating(&http.Cookie{Name: visitorCookie, Value: alice}); got != float64(8) {
+		t.Errorf("оценка зрителя %v, ожидалась 8", got)
+	}
+	// Без cookie и у зрителя без оценки поле равно null
+	if got := rating(); got != nil {
+		t.Errorf("без зрителя оценка %v, ожидался null", got)
+	}
+	if got := rating(&http.Cookie{Name: visitorCookie, Value: strings.Repeat("b", 32)}); got != nil {
+		t.Errorf("оценка другого зрителя %v, ожидался null", got)
+	}
+}
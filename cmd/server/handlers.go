@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/external"
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/metrics"
+	"movie-catalog/internal/middleware"
+	"movie-catalog/internal/models"
+)
+
+// Максимальный размер принимаемого отчёта CSP
+const maxCSPReportSize = 64 << 10
+
+// renderPage рендерит base.html с данными страницы, языком и метаданными превью ссылок
+func renderPage(c *gin.Context, data map[string]interface{}) {
+	withLocale(c, data)
+	withPageMeta(c, data)
+	renderTemplate(c, "base.html", data)
+}
+
+// renderTemplate рендерит шаблон name как HTML-ответ.
+// В данные добавляется cspNonce для встроенных скриптов.
+func renderTemplate(c *gin.Context, name string, data map[string]interface{}) {
+	data["cspNonce"] = middleware.CSPNonce(c)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	err := templates.ExecuteTemplate(c.Writer, name, data)
+	if err != nil {
+		metrics.TemplateRenderErrors.WithLabelValues(name).Inc()
+		slog.ErrorContext(c.Request.Context(), "Ошибка рендеринга шаблона", "template", name, "page", data["page"], "error", err)
+		c.String(http.StatusInternalServerError, "Ошибка рендеринга шаблона")
+	}
+}
+
+// Обработчик проверки живости процесса
+func handleHealthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Обработчик проверки готовности: 503, пока не загружены шаблоны и каталог
+// или если сервер останавливается
+func handleReadyz(c *gin.Context) {
+	status := readiness.Status()
+	code := http.StatusOK
+	if !status.Ready {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, status)
+}
+
+// Обработчик отчётов о нарушениях Content-Security-Policy.
+// Принимает как устаревший формат report-uri ({"csp-report": {...}}),
+// так и массив отчётов Reporting API, и пишет их в лог.
+func handleCSPReport(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxCSPReportSize))
+	if err != nil {
+		c.Status(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	var report interface{}
+	if err := json.Unmarshal(body, &report); err != nil {
+		slog.WarnContext(c.Request.Context(), "Некорректный отчёт CSP", "error", err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	slog.WarnContext(c.Request.Context(), "Нарушение Content-Security-Policy",
+		"report", report,
+		"user_agent", c.Request.UserAgent(),
+	)
+	c.Status(http.StatusNoContent)
+}
+
+// Обработчик главной страницы
+func handleIndex(c *gin.Context) {
+	locale := middleware.Locale(c)
+	if c.Request.URL.Path != localePath(locale, "/") {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	renderPage(c, map[string]interface{}{
+		"title":       i18n.T(locale, "page.index.title"),
+		"page":        "index",
+		"forYou":      forYou(c, locale),
+		"collections": collectionCards(locale),
+		"tagCloud":    tagCloud(locale),
+	})
+}
+
+// Обработчик страницы со всеми фильмами
+func handleMovies(c *gin.Context) {
+	renderPage(c, map[string]interface{}{
+		"title": i18n.T(middleware.Locale(c), "page.movies.title"),
+		"page":  "movies",
+	})
+}
+
+// Обработчик страницы категории
+func handleCategory(c *gin.Context) {
+	category := c.Param("category")
+
+	renderPage(c, map[string]interface{}{
+		"title":    i18n.CategoryName(middleware.Locale(c), category),
+		"category": category,
+		"pages":    "category", // Исправлено на "page" для консистентности
+	})
+}
+
+// Обработчик страницы фильма
+func handleMovie(c *gin.Context) {
+	locale := middleware.Locale(c)
+	movie, ok := movieCatalog.Movie(c.Param("id"))
+	if !ok {
+		c.String(http.StatusNotFound, i18n.T(locale, "error.movie_not_found"))
+		return
+	}
+	movie = movie.Localized(locale)
+
+	similar, _ := similarMovies(movie.ID, locale, defaultSimilarLimit)
+	userRating, inWatchlist := myMovieState(c, movie.ID)
+
+	renderPage(c, map[string]interface{}{
+		"title":        movieHeading(movie),
+		"page":         "movie",
+		"movie":        movie,
+		"categoryName": i18n.CategoryName(locale, movie.Category),
+		"links":        externalLinks(movie),
+		"directors":    personLinks(locale, movie, movie.Directors, models.RoleDirector),
+		"cast":         personLinks(locale, movie, movie.Cast, models.RoleActor),
+		"tags":         tagLinks(locale, movie.Tags),
+		"similar":      similar,
+		"userRating":   userRating,
+		"inWatchlist":  inWatchlist,
+		"ratingScale":  ratingScale(),
+		"meta":         moviePageMeta(c, movie),
+		"jsonLD":       movieJSONLD(c, movie),
+	})
+}
+
+// externalLink — ссылка на фильм во внешнем сервисе
+type externalLink struct {
+	Provider string
+	URL      string
+}
+
+// externalLinks возвращает ссылки на фильм во всех известных сервисах в порядке external.Providers
+func externalLinks(movie models.Movie) []externalLink {
+	var links []externalLink
+	for _, provider := range external.Providers() {
+		if id, ok := movie.ExternalIDs[string(provider)]; ok {
+			links = append(links, externalLink{Provider: string(provider), URL: external.URL(provider, id)})
+		}
+	}
+	return links
+}
+
+// moviePath возвращает адрес страницы фильма
+func moviePath(id string) string {
+	return "/movie/" + url.PathEscape(id)
+}
+
+// movieHeading возвращает название фильма с годом, например «Начало (2010)»
+func movieHeading(movie models.Movie) string {
+	return fmt.Sprintf("%s (%d)", movie.Title, movie.Year)
+}
+
+// Обработчик API для получения всех фильмов
+func handleAPIMovies(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", movieCatalog.Raw())
+}
+
+// Обработчик API для получения списка категорий
+func handleAPICategories(c *gin.Context) {
+	c.JSON(http.StatusOK, categoryList(i18n.Default))
+}
+
+// Обработчик API для получения фильмов по категории
+func handleAPIMoviesByCategory(c *gin.Context) {
+	movies, ok := movieCatalog.Category(c.Param("category"))
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Категория не найдена"})
+		return
+	}
+
+	c.JSON(http.StatusOK, movies)
+}
+
+// Обработчик API для получения информации о конкретном фильме
+func handleAPIMovie(c *gin.Context) {
+	movie, ok := movieCatalog.Movie(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Фильм не найден"})
+		return
+	}
+
+	c.JSON(http.StatusOK, movie)
+}
+
+// Обработчик API для поиска фильма по идентификатору во внешнем сервисе
+func handleAPIMovieByExternal(c *gin.Context) {
+	provider := external.Provider(c.Param("provider"))
+	if !provider.Known() {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Неизвестный внешний сервис"})
+		return
+	}
+	movie, ok := movieCatalog.ByExternalID(string(provider), c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Фильм не найден"})
+		return
+	}
+
+	c.JSON(http.StatusOK, movie)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/api"
+	"movie-catalog/internal/catalog"
+	"movie-catalog/internal/history"
+	"movie-catalog/internal/models"
+)
+
+// Журнал правок фильмов каталога
+var historyLog *history.Log
+
+// syncHistory записывает в журнал правки файла каталога, сделанные в обход утилит
+// и API, например вручную. Автор таких правок неизвестен.
+func syncHistory() {
+	revisions, err := historyLog.Sync(movieCatalog.Path())
+	if err != nil {
+		slog.Error("Не удалось записать правки каталога в журнал", "path", historyLog.Path(), "error", err)
+		return
+	}
+	if len(revisions) > 0 {
+		slog.Info("Правки файла каталога записаны в журнал", "revisions", len(revisions))
+	}
+}
+
+// syncHistoryOnChange записывает правки файла в журнал после перезагрузки каталога
+func syncHistoryOnChange(diff catalog.Diff) {
+	if !diff.Empty() {
+		syncHistory()
+	}
+}
+
+// movieHistory возвращает правки фильма от новых к старым; false — фильма нет
+// ни в каталоге, ни в журнале
+func movieHistory(id string) ([]models.MovieRevision, bool, error) {
+	revisions, err := historyLog.Movie(id)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(revisions) == 0 {
+		_, ok := movieCatalog.Movie(id)
+		return []models.MovieRevision{}, ok, nil
+	}
+	for i, j := 0, len(revisions)-1; i < j; i, j = i+1, j-1 {
+		revisions[i], revisions[j] = revisions[j], revisions[i]
+	}
+	return revisions, true, nil
+}
+
+// Обработчик API v1 для истории правок фильма, в том числе удалённого
+func handleV1MovieHistory(c *gin.Context) {
+	revisions, ok, err := movieHistory(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		api.Fail(c, http.StatusInternalServerError, api.CodeInternal)
+		return
+	}
+	if !ok {
+		api.Fail(c, http.StatusNotFound, api.CodeMovieNotFound)
+		return
+	}
+	api.OK(c, revisions, listMeta(len(revisions)))
+}
+
+// Обработчик API для истории правок фильма
+func handleAPIMovieHistory(c *gin.Context) {
+	revisions, ok, err := movieHistory(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Не удалось прочитать историю правок"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Фильм не найден"})
+		return
+	}
+	c.JSON(http.StatusOK, revisions)
+}
+
+// revertInput — тело запроса возврата фильма к ревизии
+type revertInput struct {
+	Revision int `json:"revision" binding:"required,min=1"`
+	// Author — кто возвращает фильм; по умолчанию admin
+	Author string `json:"author,omitempty"`
+}
+
+// Обработчик API v1 для возврата фильма к состоянию после правки из журнала.
+// Возврат записывается в журнал новой правкой, каталог сразу перечитывается.
+func handleV1RevertMovie(c *gin.Context) {
+	var input revertInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		api.FailDetails(c, http.StatusBadRequest, api.CodeBadRequest, err.Error())
+		return
+	}
+	author := history.Author{Name: strings.TrimSpace(input.Author), Source: models.SourceAPI}
+	if author.Name == "" {
+		author.Name = "admin"
+	}
+
+	reverted, err := historyLog.Revert(movieCatalog.Path(), c.Param("id"), input.Revision, author)
+	switch {
+	case errors.Is(err, history.ErrNotFound):
+		api.Fail(c, http.StatusNotFound, api.CodeRevisionNotFound)
+		return
+	case errors.Is(err, history.ErrInvalid):
+		api.FailDetails(c, http.StatusUnprocessableEntity, api.CodeValidationFailed, strings.ReplaceAll(err.Error(), "\n", "; "))
+		return
+	case err != nil:
+		c.Error(err)
+		api.Fail(c, http.StatusInternalServerError, api.CodeInternal)
+		return
+	}
+	if len(reverted) > 0 {
+		if err := movieCatalog.Reload(); err != nil {
+			c.Error(err)
+		}
+	}
+	api.OK(c, reverted, listMeta(len(reverted)))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"html/template"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/middleware"
+)
+
+// Функции шаблонов для перевода интерфейса:
+//
+//	{{ t .locale "page.index.welcome" }}           — сообщение из каталога
+//	{{ localePath .locale "/movies" }}              — ссылка с префиксом языка
+//	{{ categoryName .locale "drama" }}              — название категории
+//	{{ join .movie.Cast ", " }}                    — список через разделитель
+var templateFuncs = template.FuncMap{
+	"t":            i18n.T,
+	"localePath":   localePath,
+	"categoryName": i18n.CategoryName,
+	"join":         strings.Join,
+}
+
+// localePath добавляет к пути префикс языка, например /movies → /en/movies
+func localePath(locale, path string) string {
+	return middleware.LocalePrefix(locale) + path
+}
+
+// unprefixedPath убирает из пути запроса префикс языка
+func unprefixedPath(c *gin.Context) string {
+	path := c.Request.URL.Path
+	if prefix := middleware.LocalePrefix(middleware.Locale(c)); prefix != "" && strings.HasPrefix(path, prefix) {
+		path = strings.TrimPrefix(path, prefix)
+	}
+	if path == "" {
+		path = "/"
+	}
+	return path
+}
+
+// languageLink — ссылка переключения на другой язык
+type languageLink struct {
+	Locale string
+	Label  string
+	URL    string
+}
+
+// withLocale добавляет в данные страницы язык, сообщения для main.js
+// и ссылки переключения языка. Переключатель ведёт на ?lang=, чтобы выбор
+// запомнился в cookie и для страниц без префикса.
+func withLocale(c *gin.Context, data map[string]interface{}) {
+	locale := middleware.Locale(c)
+	data["locale"] = locale
+	data["jsMessages"] = i18n.Messages(locale, "js.", "category.", "external.")
+
+	var links []languageLink
+	for _, other := range i18n.Supported {
+		if other == locale {
+			continue
+		}
+		links = append(links, languageLink{
+			Locale: other,
+			Label:  i18n.T(other, "nav.language_name"),
+			URL:    unprefixedPath(c) + "?lang=" + other,
+		})
+	}
+	data["languages"] = links
+}
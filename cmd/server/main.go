@@ -2,220 +2,408 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"html/template"
-	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
-)
-
-// MovieWithDescription представляет информацию о фильме
-type MovieWithDescription struct {
-	ID              string `json:"id"`
-	Title           string `json:"title"`
-	Year            int    `json:"year"`
-	Category        string `json:"category"`
-	Description     string `json:"description"`
-	ImagePath       string `json:"imagePath"`
-	FullDescription string `json:"fullDescription"`
-	Link            string `json:"link"` // Добавлено для поддержки ссылок
-}
 
-// Категории фильмов
-var categoryNames = map[string]string{
-	"drama":      "Драма",
-	"comedy":     "Комедия",
-	"fantasy":    "Фантастика и фэнтези",
-	"thriller":   "Триллер и детектив",
-	"biography":  "Биографический",
-	"historical": "Исторический и военный",
-	"melodrama":  "Мелодрама",
-}
+	"movie-catalog/internal/catalog"
+	"movie-catalog/internal/certs"
+	"movie-catalog/internal/collections"
+	"movie-catalog/internal/config"
+	"movie-catalog/internal/graph"
+	"movie-catalog/internal/health"
+	"movie-catalog/internal/history"
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/logger"
+	"movie-catalog/internal/metrics"
+	"movie-catalog/internal/middleware"
+	"movie-catalog/internal/movienight"
+	"movie-catalog/internal/ratings"
+	"movie-catalog/internal/recommend"
+)
 
 // Шаблоны
 var templates *template.Template
 
+// Каталог фильмов
+var movieCatalog *catalog.Catalog
+
+// Проверка готовности для /readyz
+var readiness *health.Probe
+
 func main() {
+	cfg := config.Load()
+
+	// Структурированный логгер
+	log := logger.New(os.Stdout, cfg.LogLevel)
+	slog.SetDefault(log)
+
+	publicURL = cfg.PublicURL
+	adminToken = cfg.AdminToken
+	movieCatalog = catalog.New(cfg.CatalogPath)
+	readiness = health.NewProbe(movieCatalog)
+
 	// Загружаем шаблоны
 	var err error
-	templates, err = template.ParseGlob("templates/*.html")
+	templates, err = template.New("").Funcs(templateFuncs).ParseGlob("templates/*.html")
 	if err != nil {
-		log.Fatalf("Ошибка при загрузке шаблонов: %v", err)
+		log.Error("Ошибка при загрузке шаблонов", "error", err)
+		os.Exit(1)
+	}
+	readiness.SetTemplatesReady()
+
+	// Загружаем каталог и следим за изменениями файла
+	movieCatalog.OnReload(metrics.ObserveReload)
+	movieCatalog.OnReload(rebuildSimilarIndex)
+	movieCatalog.OnReload(func(err error) {
+		if err != nil {
+			log.Error("Ошибка при загрузке каталога", "path", movieCatalog.Path(), "error", err)
+			return
+		}
+		log.Info("Каталог загружен", "path", movieCatalog.Path(), "categories", len(movieCatalog.Categories()))
+	})
+	if err := movieCatalog.Reload(); err != nil {
+		os.Exit(1)
+	}
+	// Правки файла, сделанные пока сервер не работал, попадают в журнал сразу,
+	// а следующие — после каждой перезагрузки каталога
+	historyLog = history.New(cfg.HistoryPath)
+	syncHistory()
+	movieCatalog.OnChange(syncHistoryOnChange)
+	// Изменения после первой загрузки рассылаются подписчикам /api/events
+	movieCatalog.OnChange(publishCatalogDiff)
+	metrics.RegisterCatalogSize(movieCatalog.Sizes)
+
+	collectionStore, err = collections.Open(cfg.CollectionsPath)
+	if err != nil {
+		log.Error("Ошибка при загрузке подборок", "path", cfg.CollectionsPath, "error", err)
+		os.Exit(1)
 	}
 
-	// Настройка Gin
-	gin.SetMode(gin.ReleaseMode) // Используйте gin.DebugMode для отладки
-	router := gin.Default()
+	ratingStore, err = ratings.Open(cfg.RatingsPath)
+	if err != nil {
+		log.Error("Ошибка при загрузке оценок", "path", cfg.RatingsPath, "error", err)
+		os.Exit(1)
+	}
+	nightStore, err = movienight.Open(cfg.NightsPath)
+	if err != nil {
+		log.Error("Ошибка при загрузке голосований", "path", cfg.NightsPath, "error", err)
+		os.Exit(1)
+	}
+	recommender = recommend.NewJob(ratingStore, cfg.RecommendInterval, cfg.RecommendCPUShare, log)
 
-	// Статические файлы
-	router.Static("/static", "./static")
+	graphQLSchema, err = graph.NewSchema(movieCatalog, ratingStore)
+	if err != nil {
+		log.Error("Ошибка построения GraphQL-схемы", "error", err)
+		os.Exit(1)
+	}
 
-	// Маршруты
-	router.GET("/", handleIndex)
-	router.GET("/movies", handleMovies)
-	router.GET("/category/:category", handleCategory)
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go movieCatalog.Watch(watchCtx, cfg.CatalogReloadInterval)
+	go recommender.Run(watchCtx)
+	nightStore.OnChange(publishNight)
+	go nightStore.Run(watchCtx, logNightWinner)
+	go ratingStore.Run(watchCtx, log)
 
-	// API маршруты
-	router.GET("/api/movies", handleAPIMovies)
-	router.GET("/api/movies/:category", handleAPIMoviesByCategory)
-	router.GET("/api/movie/:id", handleAPIMovie)
+	router, err := setupRouter(log, cfg)
+	if err != nil {
+		log.Error("Ошибка настройки маршрутов", "error", err)
+		os.Exit(1)
+	}
 
 	// Настройка HTTP-сервера
 	filmsServer := &http.Server{
-		Addr:    ":8080", // Можно изменить на "localhost:8080", если нужен только локальный доступ
-		Handler: router,
+		Addr:     cfg.Addr, // Можно изменить на "localhost:8080" через ADDR, если нужен только локальный доступ
+		Handler:  router,
+		ErrorLog: slog.NewLogLogger(log.Handler(), slog.LevelError),
+	}
+
+	// При включённом HTTPS основной сервер слушает TLS_ADDR, а ADDR только
+	// перенаправляет на HTTPS и отвечает на ACME-проверки
+	var redirectServer *http.Server
+	if cfg.TLS.Enabled() {
+		tlsConfig, redirectHandler, err := certs.Setup(cfg.TLS)
+		if err != nil {
+			log.Error("Ошибка настройки HTTPS", "error", err)
+			os.Exit(1)
+		}
+		filmsServer.Addr = cfg.TLS.Addr
+		filmsServer.TLSConfig = tlsConfig
+
+		redirectServer = &http.Server{
+			Addr:     cfg.Addr,
+			Handler:  redirectHandler,
+			ErrorLog: slog.NewLogLogger(log.Handler(), slog.LevelError),
+		}
 	}
 
-	// Запуск сервера в горутине
+	// Служебный сервер с метриками на отдельном адресе
+	adminMux := http.NewServeMux()
+	adminMux.Handle("/metrics", metrics.Handler())
+	adminServer := &http.Server{
+		Addr:     cfg.AdminAddr,
+		Handler:  adminMux,
+		ErrorLog: slog.NewLogLogger(log.Handler(), slog.LevelError),
+	}
+
+	// Запуск серверов в горутинах
 	go func() {
-		log.Printf("Сервер запущен на http://localhost%s", filmsServer.Addr)
-		if err := filmsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Ошибка запуска HTTP-сервера: %v", err)
+		var err error
+		if filmsServer.TLSConfig != nil {
+			log.Info("HTTPS-сервер запущен", "addr", filmsServer.Addr)
+			err = filmsServer.ListenAndServeTLS("", "")
+		} else {
+			log.Info("Сервер запущен", "addr", filmsServer.Addr)
+			err = filmsServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Error("Ошибка запуска HTTP-сервера", "error", err)
+			os.Exit(1)
+		}
+	}()
+	if redirectServer != nil {
+		go func() {
+			log.Info("Сервер перенаправления на HTTPS запущен", "addr", redirectServer.Addr)
+			if err := redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Error("Ошибка запуска сервера перенаправления", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+	go func() {
+		log.Info("Служебный сервер запущен", "addr", adminServer.Addr)
+		if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error("Ошибка запуска служебного сервера", "error", err)
+			os.Exit(1)
 		}
 	}()
 
-	// Ожидание сигнала завершения
+	// Ожидание сигнала завершения; SIGHUP перечитывает каталог
 	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGHUP)
 	sig := <-signalChan
-	log.Printf("Получен сигнал завершения: %v", sig)
+	for sig == syscall.SIGHUP {
+		movieCatalog.Reload()
+		sig = <-signalChan
+	}
+	log.Info("Получен сигнал завершения", "signal", sig.String())
+
+	// Сначала сообщаем прокси, что сервер больше не готов, и даём время снять его с балансировки
+	readiness.SetShuttingDown()
+	log.Info("Ожидание снятия трафика перед остановкой", "delay", cfg.ShutdownDrainDelay.String())
+	select {
+	case <-time.After(cfg.ShutdownDrainDelay):
+	case sig = <-signalChan:
+		log.Info("Повторный сигнал, останавливаемся без ожидания", "signal", sig.String())
+	}
+
+	// Долгие SSE-соединения сами не завершатся, поэтому закрываем их до Shutdown
+	eventBroker.Close()
+	closeNightBrokers()
 
-	// Graceful shutdown с таймаутом 5 секунд
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// Graceful shutdown с таймаутом
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
 	if err := filmsServer.Shutdown(shutdownCtx); err != nil {
-		log.Printf("Ошибка при остановке HTTP-сервера: %v", err)
+		log.Error("Ошибка при остановке HTTP-сервера", "error", err)
 	} else {
-		log.Println("HTTP-сервер успешно остановлен")
+		log.Info("HTTP-сервер успешно остановлен")
 	}
-
-	log.Println("Сервер завершил работу")
-}
-
-// Обработчик главной страницы
-func handleIndex(c *gin.Context) {
-	if c.Request.URL.Path != "/" {
-		c.Status(http.StatusNotFound)
-		return
-	}
-
-	data := map[string]interface{}{
-		"title": "Каталог фильмов",
-		"page":  "index",
+	if redirectServer != nil {
+		if err := redirectServer.Shutdown(shutdownCtx); err != nil {
+			log.Error("Ошибка при остановке сервера перенаправления", "error", err)
+		}
 	}
-
-	err := templates.ExecuteTemplate(c.Writer, "base.html", data)
-	if err != nil {
-		c.String(http.StatusInternalServerError, "Ошибка рендеринга шаблона: %v", err)
+	if err := adminServer.Shutdown(shutdownCtx); err != nil {
+		log.Error("Ошибка при остановке служебного сервера", "error", err)
 	}
-}
-
-// Обработчик страницы со всеми фильмами
-func handleMovies(c *gin.Context) {
-	data := map[string]interface{}{
-		"title": "Все фильмы",
-		"page":  "movies",
+	// Оценки записываются в фоне: сохраняем то, что не успело попасть в файл
+	if err := ratingStore.Flush(); err != nil {
+		log.Error("Ошибка при сохранении оценок", "path", cfg.RatingsPath, "error", err)
 	}
 
-	err := templates.ExecuteTemplate(c.Writer, "base.html", data)
-	if err != nil {
-		c.String(http.StatusInternalServerError, "Ошибка рендеринга шаблона: %v", err)
-	}
+	log.Info("Сервер завершил работу")
 }
 
-// Обработчик страницы категории
-func handleCategory(c *gin.Context) {
-	category := c.Param("category")
-	categoryTitle, ok := categoryNames[category]
-	if !ok {
-		categoryTitle = category
-	}
-
-	data := map[string]interface{}{
-		"title":    categoryTitle,
-		"category": category,
-		"pages":    "category", // Исправлено на "page" для консистентности
-	}
-
-	err := templates.ExecuteTemplate(c.Writer, "base.html", data)
-	if err != nil {
-		c.String(http.StatusInternalServerError, "Ошибка рендеринга шаблона: %v", err)
-	}
+// rateLimiters — ограничители частоты запросов с одного IP по группам маршрутов
+type rateLimiters struct {
+	static gin.HandlerFunc
+	pages  gin.HandlerFunc
+	api    gin.HandlerFunc
+	// write — для изменяющих запросов (правки, оценки, комментарии), самый строгий
+	write gin.HandlerFunc
 }
 
-// Обработчик API для получения всех фильмов
-func handleAPIMovies(c *gin.Context) {
-	jsonFilePath := filepath.Join("static", "data", "movies.json")
-	jsonData, err := os.ReadFile(jsonFilePath)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось загрузить данные о фильмах"})
-		return
+func newRateLimiters(limits config.RateLimits) rateLimiters {
+	return rateLimiters{
+		static: middleware.NewIPRateLimiter(limits.Static).Handler(handleRateLimited),
+		pages:  middleware.NewIPRateLimiter(limits.Pages).Handler(handleRateLimited),
+		api:    middleware.NewIPRateLimiter(limits.API).Handler(handleRateLimited),
+		write:  middleware.NewIPRateLimiter(limits.Write).Handler(handleRateLimited),
 	}
-
-	c.Header("Content-Type", "application/json")
-	c.Writer.Write(jsonData)
 }
 
-// Обработчик API для получения фильмов по категории
-func handleAPIMoviesByCategory(c *gin.Context) {
-	category := c.Param("category")
+// setupRouter настраивает middleware и маршруты
+func setupRouter(log *slog.Logger, cfg config.Config) (*gin.Engine, error) {
+	gin.SetMode(gin.ReleaseMode) // Используйте gin.DebugMode для отладки
+	router := gin.New()
 
-	jsonFilePath := filepath.Join("static", "data", "movies.json")
-	jsonData, err := os.ReadFile(jsonFilePath)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось загрузить данные о фильмах"})
-		return
+	// X-Forwarded-For учитывается только от явно указанных прокси,
+	// иначе клиент мог бы подставить любой адрес и обойти ограничения
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, err
 	}
-
-	var moviesByCategory map[string][]MovieWithDescription
-	err = json.Unmarshal(jsonData, &moviesByCategory)
+	proxies, err := parseProxies(cfg.TrustedProxies)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обработке данных о фильмах"})
-		return
+		return nil, err
 	}
-
-	movies, ok := moviesByCategory[category]
-	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Категория не найдена"})
-		return
+	trustedProxies = proxies
+	allowedHosts = cfg.AllowedHosts
+
+	router.Use(middleware.RequestID(), middleware.Logger(log), middleware.Recovery(log))
+	router.Use(middleware.Metrics())
+	router.Use(middleware.HSTS(cfg.TLS.HSTSMaxAge, cfg.TLS.HSTSIncludeSubdomains))
+	router.Use(middleware.SecurityHeaders(middleware.SecurityOptions{
+		ScriptSources: []string{"https://cdn.jsdelivr.net"},
+		StyleSources:  []string{"https://cdn.jsdelivr.net"},
+		ImageSources:  movieCatalog.ImageHosts,
+		ReportURI:     "/csp-report",
+		ReportOnly:    cfg.CSPReportOnly,
+	}))
+	router.Use(middleware.Compress())
+	router.Use(middleware.DetectLocale())
+
+	limits := newRateLimiters(cfg.RateLimits)
+
+	// Статические файлы (с предварительно сжатыми версиями, если они собраны)
+	staticGroup := router.Group("/static", limits.static, middleware.Precompressed("/static", "./static"))
+	staticGroup.Static("/", "./static")
+
+	// Проверки живости и готовности
+	router.GET("/healthz", handleHealthz)
+	router.GET("/readyz", handleReadyz)
+
+	// Отчёты браузеров о нарушениях Content-Security-Policy
+	router.POST("/csp-report", limits.api, handleCSPReport)
+
+	// Страницы и ленты; для языков, кроме русского, те же маршруты доступны с префиксом (/en/movies)
+	for _, locale := range i18n.Supported {
+		pages := router.Group(middleware.LocalePrefix(locale)+"/", limits.pages)
+		pages.GET("/", handleIndex)
+		pages.GET("/movies", handleMovies)
+		pages.GET("/category/:category", handleCategory)
+		pages.GET("/movie/:id", handleMovie)
+		pages.GET("/person/:id", handlePerson)
+		pages.GET("/collection/:slug", handleCollection)
+		pages.GET("/tag/:tag", handleTag)
+		pages.GET("/night", handleNewNight)
+		pages.GET("/night/:id", handleNight)
+
+		// Ленты RSS и Atom последних добавленных фильмов
+		pages.GET("/feed.rss", handleFeedRSS)
+		pages.GET("/feed.atom", handleFeedAtom)
+		pages.GET("/category/:category/feed.rss", handleCategoryFeedRSS)
 	}
 
-	c.JSON(http.StatusOK, movies)
+	// Карта сайта и правила для поисковых роботов
+	router.GET("/sitemap.xml", limits.pages, handleSitemap)
+	router.GET("/robots.txt", limits.pages, handleRobots)
+
+	// API v1 с единым форматом ответов
+	v1 := router.Group(apiV1Prefix, limits.api)
+	v1.GET("/movies", handleV1Movies)
+	v1.GET("/movies/random", privateResponse, handleV1RandomMovie)
+	v1.GET("/movies/:id", handleV1Movie)
+	v1.GET("/movies/:id/similar", handleV1SimilarMovies)
+	v1.GET("/movies/:id/history", handleV1MovieHistory)
+	v1.GET("/movies/by-external/:provider/:id", handleV1MovieByExternal)
+	v1.GET("/people/:id", handleV1Person)
+	v1.GET("/categories", handleV1Categories)
+	v1.GET("/categories/:category/movies", handleV1CategoryMovies)
+	v1.GET("/tags", handleV1Tags)
+	v1.GET("/collections", handleV1Collections)
+	v1.GET("/collections/:slug", handleV1Collection)
+
+	// Оценки, список «посмотреть позже» и рекомендации анонимного зрителя из cookie visitor
+	me := v1.Group("/me", privateResponse)
+	me.GET("/ratings", handleV1MyRatings)
+	me.PUT("/ratings/:id", limits.write, handleV1RateMovie)
+	me.DELETE("/ratings/:id", limits.write, handleV1UnrateMovie)
+	me.GET("/watchlist", handleV1MyWatchlist)
+	me.PUT("/watchlist/:id", limits.write, handleV1AddToWatchlist)
+	me.DELETE("/watchlist/:id", limits.write, handleV1RemoveFromWatchlist)
+	me.GET("/recommendations", handleV1Recommendations)
+
+	// Голосования за фильм на вечер: участники определяются по cookie visitor
+	nights := v1.Group("/nights", privateResponse)
+	nights.POST("", limits.write, handleV1CreateNight)
+	nights.GET("/:id", handleV1Night)
+	nights.PUT("/:id/ballot", limits.write, handleV1VoteNight)
+	nights.POST("/:id/close", limits.write, handleV1CloseNight)
+	nights.GET("/:id/events", handleV1NightEvents)
+
+	// Изменение подборок: только с токеном администратора и со строгим лимитом
+	v1.POST("/collections", limits.write, requireAdmin, handleV1CreateCollection)
+	v1.PUT("/collections/:slug", limits.write, requireAdmin, handleV1UpdateCollection)
+	v1.DELETE("/collections/:slug", limits.write, requireAdmin, handleV1DeleteCollection)
+
+	// Возврат фильма к ревизии из журнала правок: тоже только для администратора
+	v1.POST("/movies/:id/revert", limits.write, requireAdmin, handleV1RevertMovie)
+
+	// Устаревшие маршруты API, сохранены для совместимости со сторонними скриптами
+	api := router.Group("/api", limits.api)
+	api.GET("/movies", deprecatedAPI(func(c *gin.Context) string {
+		return apiV1Prefix + "/movies"
+	}), handleAPIMovies)
+	api.GET("/movies/:category", deprecatedAPI(func(c *gin.Context) string {
+		return apiV1Prefix + "/categories/" + url.PathEscape(c.Param("category")) + "/movies"
+	}), handleAPIMoviesByCategory)
+	api.GET("/movie/:id", deprecatedAPI(func(c *gin.Context) string {
+		return apiV1Prefix + "/movies/" + url.PathEscape(c.Param("id"))
+	}), handleAPIMovie)
+	api.GET("/movie/:id/similar", handleAPISimilarMovies)
+	api.GET("/movie/:id/history", handleAPIMovieHistory)
+	api.GET("/movie/by-external/:provider/:id", deprecatedAPI(func(c *gin.Context) string {
+		return apiV1Prefix + "/movies/by-external/" + url.PathEscape(c.Param("provider")) + "/" + url.PathEscape(c.Param("id"))
+	}), handleAPIMovieByExternal)
+	api.GET("/person/:id", handleAPIPerson)
+	api.GET("/categories", deprecatedAPI(func(c *gin.Context) string {
+		return apiV1Prefix + "/categories"
+	}), handleAPICategories)
+	api.GET("/tags", handleAPITags)
+	api.GET("/random", privateResponse, handleAPIRandomMovie)
+	api.GET("/me/recommendations", privateResponse, handleAPIRecommendations)
+
+	// Поток изменений каталога
+	api.GET("/events", handleAPIEvents)
+
+	// Документация API
+	api.GET("/openapi.json", handleAPIOpenAPI)
+	api.GET("/docs", handleAPIDocs)
+
+	// GraphQL для выборки только нужных полей и консоль GraphiQL
+	router.GET("/graphql", limits.api, handleGraphQL)
+	router.POST("/graphql", limits.api, handleGraphQL)
+	router.GET("/graphiql", limits.pages, handleGraphiQL)
+
+	router.NoRoute(handleNoRoute)
+
+	return router, nil
 }
 
-// Обработчик API для получения информации о конкретном фильме
-func handleAPIMovie(c *gin.Context) {
-	movieID := c.Param("id")
-
-	jsonFilePath := filepath.Join("static", "data", "movies.json")
-	jsonData, err := os.ReadFile(jsonFilePath)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось загрузить данные о фильмах"})
-		return
-	}
-
-	var moviesByCategory map[string][]MovieWithDescription
-	err = json.Unmarshal(jsonData, &moviesByCategory)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обработке данных о фильмах"})
-		return
-	}
-
-	for _, movies := range moviesByCategory {
-		for _, movie := range movies {
-			if movie.ID == movieID {
-				c.JSON(http.StatusOK, movie)
-				return
-			}
-		}
-	}
+// Дата, с которой маршруты API без версии считаются устаревшими
+var legacyAPIDeprecatedSince = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
 
-	c.JSON(http.StatusNotFound, gin.H{"error": "Фильм не найден"})
+// deprecatedAPI помечает маршрут API без версии как устаревший со ссылкой на замену в v1
+func deprecatedAPI(successor func(c *gin.Context) string) gin.HandlerFunc {
+	return middleware.Deprecated(legacyAPIDeprecatedSince, successor)
 }
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/api"
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/models"
+	"movie-catalog/internal/ratings"
+	"movie-catalog/internal/recommend"
+)
+
+// visitorCookie — cookie с анонимным идентификатором зрителя, к которому привязаны
+// его оценки и список «посмотреть позже». Выдаётся при первой оценке или закладке.
+const visitorCookie = "visitor"
+
+// Срок хранения cookie зрителя — год
+const visitorCookieMaxAge = 365 * 24 * 60 * 60
+
+var visitorPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// Количество рекомендаций по умолчанию и наибольшее, которое можно запросить
+const (
+	defaultRecommendationsLimit = 12
+	maxRecommendationsLimit     = 50
+)
+
+// Количество рекомендаций в разделе «Для вас» на главной странице
+const forYouLimit = 6
+
+// Оценки и списки зрителей
+var ratingStore *ratings.Store
+
+// Фоновая перестройка модели рекомендаций
+var recommender *recommend.Job
+
+// visitorID возвращает идентификатор зрителя из cookie или пустую строку
+func visitorID(c *gin.Context) string {
+	id, err := c.Cookie(visitorCookie)
+	if err != nil || !visitorPattern.MatchString(id) {
+		return ""
+	}
+	return id
+}
+
+// ensureVisitor возвращает идентификатор зрителя, при необходимости выдавая новый
+func ensureVisitor(c *gin.Context) (string, error) {
+	if id := visitorID(c); id != "" {
+		return id, nil
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(buf)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(visitorCookie, id, visitorCookieMaxAge, "/", "", c.Request.TLS != nil, true)
+	return id, nil
+}
+
+// privateResponse запрещает общим кешам хранить ответы, зависящие от зрителя
+func privateResponse(c *gin.Context) {
+	c.Header("Cache-Control", "private, no-store")
+	c.Next()
+}
+
+// recommendationsFor подбирает зрителю фильмы на языке locale
+func recommendationsFor(userID, locale string, limit int) []models.Recommendation {
+	profile := recommend.Profile{Ratings: make(map[string]float64)}
+	for _, r := range ratingStore.Ratings(userID) {
+		profile.Ratings[r.MovieID] = float64(r.Rating)
+	}
+	for _, item := range ratingStore.Watchlist(userID) {
+		profile.Watchlist = append(profile.Watchlist, item.MovieID)
+	}
+
+	similar := func(movieID string, limit int) []recommend.Suggestion {
+		movies, _ := similarMovies(movieID, i18n.Default, limit)
+		suggestions := make([]recommend.Suggestion, len(movies))
+		for i, movie := range movies {
+			suggestions[i] = recommend.Suggestion{MovieID: movie.ID, Score: movie.Score}
+		}
+		return suggestions
+	}
+	available := func(movieID string) bool {
+		_, ok := movieCatalog.Movie(movieID)
+		return ok
+	}
+
+	suggestions := recommender.Model().Recommend(profile, limit, similar, available)
+	result := make([]models.Recommendation, 0, len(suggestions))
+	for _, s := range suggestions {
+		movie, ok := movieCatalog.Movie(s.MovieID)
+		if !ok {
+			continue
+		}
+		result = append(result, models.Recommendation{
+			Movie:     movie.Localized(locale),
+			Score:     s.Score,
+			Reason:    s.Reason,
+			BecauseOf: s.BecauseOf,
+		})
+	}
+	return result
+}
+
+// forYou возвращает рекомендации для раздела «Для вас»; пусто, пока зритель ничего не оценил и не отложил
+func forYou(c *gin.Context, locale string) []models.Recommendation {
+	userID := visitorID(c)
+	if userID == "" {
+		return nil
+	}
+	c.Header("Cache-Control", "private")
+	if len(ratingStore.Ratings(userID)) == 0 && len(ratingStore.Watchlist(userID)) == 0 {
+		return nil
+	}
+	return recommendationsFor(userID, locale, forYouLimit)
+}
+
+// myMovieState возвращает оценку зрителя и наличие фильма в его списке «посмотреть позже»
+// для страницы фильма
+func myMovieState(c *gin.Context, movieID string) (int, bool) {
+	userID := visitorID(c)
+	if userID == "" {
+		return 0, false
+	}
+	c.Header("Cache-Control", "private")
+	rating, _ := ratingStore.Rating(userID, movieID)
+	return rating, ratingStore.InWatchlist(userID, movieID)
+}
+
+// ratingScale возвращает допустимые оценки по возрастанию для кнопок на странице фильма
+func ratingScale() []int {
+	scale := make([]int, 0, models.MaxRating-models.MinRating+1)
+	for value := models.MinRating; value <= models.MaxRating; value++ {
+		scale = append(scale, value)
+	}
+	return scale
+}
+
+// ratingInput — тело запроса оценки фильма
+type ratingInput struct {
+	Rating int `json:"rating"`
+}
+
+// movieForMe проверяет, что фильм из пути есть в каталоге; иначе отвечает 404
+func movieForMe(c *gin.Context) (string, bool) {
+	movieID := c.Param("id")
+	if _, ok := movieCatalog.Movie(movieID); !ok {
+		api.Fail(c, http.StatusNotFound, api.CodeMovieNotFound)
+		return "", false
+	}
+	return movieID, true
+}
+
+// Обработчик API v1 для оценок зрителя
+func handleV1MyRatings(c *gin.Context) {
+	list := ratingStore.Ratings(visitorID(c))
+	api.OK(c, list, listMeta(len(list)))
+}
+
+// Обработчик API v1 для оценки фильма зрителем
+func handleV1RateMovie(c *gin.Context) {
+	movieID, ok := movieForMe(c)
+	if !ok {
+		return
+	}
+	var input ratingInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		api.FailDetails(c, http.StatusBadRequest, api.CodeBadRequest, err.Error())
+		return
+	}
+	userID, err := ensureVisitor(c)
+	if err != nil {
+		c.Error(err)
+		api.Fail(c, http.StatusInternalServerError, api.CodeInternal)
+		return
+	}
+
+	rating, err := ratingStore.Rate(userID, movieID, input.Rating)
+	switch {
+	case errors.Is(err, ratings.ErrInvalidRating):
+		api.FailDetails(c, http.StatusUnprocessableEntity, api.CodeValidationFailed, err.Error())
+	case err != nil:
+		c.Error(err)
+		api.Fail(c, http.StatusInternalServerError, api.CodeInternal)
+	default:
+		api.OK(c, rating, itemMeta())
+	}
+}
+
+// Обработчик API v1 для удаления оценки фильма
+func handleV1UnrateMovie(c *gin.Context) {
+	if err := ratingStore.Unrate(visitorID(c), c.Param("id")); err != nil {
+		c.Error(err)
+		api.Fail(c, http.StatusInternalServerError, api.CodeInternal)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Обработчик API v1 для списка «посмотреть позже»
+func handleV1MyWatchlist(c *gin.Context) {
+	list := ratingStore.Watchlist(visitorID(c))
+	api.OK(c, list, listMeta(len(list)))
+}
+
+// Обработчик API v1 для добавления фильма в список «посмотреть позже»
+func handleV1AddToWatchlist(c *gin.Context) {
+	movieID, ok := movieForMe(c)
+	if !ok {
+		return
+	}
+	userID, err := ensureVisitor(c)
+	if err == nil {
+		err = ratingStore.AddToWatchlist(userID, movieID)
+	}
+	if err != nil {
+		c.Error(err)
+		api.Fail(c, http.StatusInternalServerError, api.CodeInternal)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Обработчик API v1 для удаления фильма из списка «посмотреть позже»
+func handleV1RemoveFromWatchlist(c *gin.Context) {
+	if err := ratingStore.RemoveFromWatchlist(visitorID(c), c.Param("id")); err != nil {
+		c.Error(err)
+		api.Fail(c, http.StatusInternalServerError, api.CodeInternal)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Обработчик API v1 для персональных рекомендаций
+func handleV1Recommendations(c *gin.Context) {
+	limit, ok := queryLimit(c, defaultRecommendationsLimit, maxRecommendationsLimit)
+	if !ok {
+		api.Fail(c, http.StatusBadRequest, api.CodeBadRequest)
+		return
+	}
+	list := recommendationsFor(visitorID(c), api.Locale(c), limit)
+	api.OK(c, list, listMeta(len(list)))
+}
+
+// Обработчик API для персональных рекомендаций
+func handleAPIRecommendations(c *gin.Context) {
+	limit, ok := queryLimit(c, defaultRecommendationsLimit, maxRecommendationsLimit)
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Некорректный параметр limit"})
+		return
+	}
+	c.JSON(http.StatusOK, recommendationsFor(visitorID(c), i18n.Default, limit))
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/api"
+	"movie-catalog/internal/events"
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/middleware"
+	"movie-catalog/internal/models"
+	"movie-catalog/internal/movienight"
+)
+
+// Голосования за фильм на вечер
+var nightStore *movienight.Store
+
+// Срок голосования, который форма создания предлагает по умолчанию
+const defaultNightDuration = 3 * time.Hour
+
+// nightBrokers — брокеры событий голосований для подписчиков
+// /api/v1/nights/{id}/events. Брокер создаётся при первой подписке
+// и закрывается, когда голосование завершилось.
+var nightBrokers = struct {
+	sync.Mutex
+	m      map[string]*events.Broker
+	closed bool
+}{m: make(map[string]*events.Broker)}
+
+// subscribeNight возвращает текущие итоги голосования id и, если оно ещё идёт,
+// подписку на его события; found равен false, если голосования нет. Подписка
+// оформляется внутри nightStore.Watch: пока она не готова, новые голоса не
+// публикуются, поэтому подписчик не пропустит ни голоса, ни night.closed.
+func subscribeNight(id string) (results models.NightResults, stream <-chan events.Event, unsubscribe func(), found bool) {
+	nightStore.Watch(id, func(current models.NightResults, ok bool) {
+		results, found = current, ok
+		if !ok || current.Night.Closed() {
+			return
+		}
+		nightBrokers.Lock()
+		defer nightBrokers.Unlock()
+		broker, ok := nightBrokers.m[id]
+		if !ok {
+			broker = events.NewBroker()
+			if nightBrokers.closed {
+				broker.Close()
+			} else {
+				nightBrokers.m[id] = broker
+			}
+		}
+		stream, unsubscribe = broker.Subscribe()
+	})
+	return results, stream, unsubscribe, found
+}
+
+// publishNight рассылает подписчикам новые итоги голосования. Вызывается хранилищем
+// под его блокировкой (см. movienight.Store.OnChange), поэтому итоги уходят в порядке
+// голосов. После завершения голосования брокер закрывается: подписчики получают
+// night.closed и отключаются.
+func publishNight(results models.NightResults) {
+	nightBrokers.Lock()
+	defer nightBrokers.Unlock()
+	broker, ok := nightBrokers.m[results.Night.ID]
+	if !ok {
+		return
+	}
+	if results.Night.Closed() {
+		broker.Publish(events.NightClosed, results)
+		broker.Close()
+		delete(nightBrokers.m, results.Night.ID)
+		return
+	}
+	broker.Publish(events.NightUpdated, results)
+}
+
+// logNightWinner записывает в лог итоги голосования, завершившегося в срок.
+// Участникам их рассылает publishNight.
+func logNightWinner(results models.NightResults) {
+	slog.Info("Голосование завершено", "night", results.Night.ID, "winner", results.Night.Winner, "voters", results.Voters)
+}
+
+// closeNightBrokers отключает всех подписчиков голосований перед остановкой сервера
+func closeNightBrokers() {
+	nightBrokers.Lock()
+	defer nightBrokers.Unlock()
+	nightBrokers.closed = true
+	for id, broker := range nightBrokers.m {
+		broker.Close()
+		delete(nightBrokers.m, id)
+	}
+}
+
+// nightEventType возвращает тип события для текущего состояния голосования
+func nightEventType(night models.MovieNight) string {
+	if night.Closed() {
+		return events.NightClosed
+	}
+	return events.NightUpdated
+}
+
+// nightPath возвращает адрес страницы голосования
+func nightPath(id string) string {
+	return "/night/" + url.PathEscape(id)
+}
+
+// nightCandidate — фильм голосования на странице с голосами и выбором зрителя
+type nightCandidate struct {
+	Movie models.Movie
+	Votes int
+	// Rank — место фильма в бюллетене зрителя начиная с 1; 0 — не выбран
+	Rank int
+}
+
+// nightCandidates собирает фильмы голосования на языке locale в порядке создателя
+func nightCandidates(results models.NightResults, ballot models.NightBallot, locale string) []nightCandidate {
+	votes := make(map[string]int, len(results.Tally))
+	for _, t := range results.Tally {
+		votes[t.MovieID] = t.Votes
+	}
+	ranks := make(map[string]int, len(ballot.Choices))
+	for i, id := range ballot.Choices {
+		ranks[id] = i + 1
+	}
+	candidates := make([]nightCandidate, 0, len(results.Night.MovieIDs))
+	for _, id := range results.Night.MovieIDs {
+		movie, ok := movieCatalog.Movie(id)
+		if !ok {
+			movie = models.Movie{ID: id, Title: id}
+		}
+		candidates = append(candidates, nightCandidate{Movie: movie.Localized(locale), Votes: votes[id], Rank: ranks[id]})
+	}
+	return candidates
+}
+
+// nightRanks возвращает места 1..n для выбора в бюллетене ranked
+func nightRanks(n int) []int {
+	ranks := make([]int, n)
+	for i := range ranks {
+		ranks[i] = i + 1
+	}
+	return ranks
+}
+
+// nightMovieOption — фильм в форме создания голосования
+type nightMovieOption struct {
+	ID    string
+	Title string
+	Year  int
+}
+
+// nightMovieGroup — фильмы одной категории в форме создания голосования
+type nightMovieGroup struct {
+	Name   string
+	Movies []nightMovieOption
+}
+
+// nightMovieGroups собирает все фильмы каталога по категориям для формы создания голосования
+func nightMovieGroups(locale string) []nightMovieGroup {
+	var groups []nightMovieGroup
+	for _, category := range movieCatalog.Categories() {
+		group := nightMovieGroup{Name: i18n.CategoryName(locale, category)}
+		movies, _ := movieCatalog.Category(category)
+		for _, movie := range movies {
+			movie = movie.Localized(locale)
+			group.Movies = append(group.Movies, nightMovieOption{ID: movie.ID, Title: movie.Title, Year: movie.Year})
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// Обработчик страницы создания голосования
+func handleNewNight(c *gin.Context) {
+	locale := middleware.Locale(c)
+	renderPage(c, map[string]interface{}{
+		"title":           i18n.T(locale, "page.night_new.title"),
+		"page":            "night-new",
+		"movieGroups":     nightMovieGroups(locale),
+		"minMovies":       movienight.MinMovies,
+		"maxMovies":       movienight.MaxMovies,
+		"defaultDuration": int(defaultNightDuration.Minutes()),
+	})
+}
+
+// Обработчик страницы голосования: фильмы, бюллетень зрителя и итоги, которые
+// обновляются по событиям /api/v1/nights/{id}/events
+func handleNight(c *gin.Context) {
+	locale := middleware.Locale(c)
+	id := c.Param("id")
+	results, ok := nightStore.Results(id)
+	if !ok {
+		c.String(http.StatusNotFound, i18n.T(locale, "error.night_not_found"))
+		return
+	}
+	// Страница зависит от зрителя и меняется с каждым голосом
+	c.Header("Cache-Control", "private, no-store")
+	voter := visitorID(c)
+	ballot, _ := nightStore.Ballot(id, voter)
+
+	var winner models.Movie
+	if results.Night.Winner != "" {
+		winner, _ = movieCatalog.Movie(results.Night.Winner)
+		winner = winner.Localized(locale)
+	}
+
+	candidates := nightCandidates(results, ballot, locale)
+	titles := make(map[string]string, len(candidates))
+	for _, candidate := range candidates {
+		titles[candidate.Movie.ID] = candidate.Movie.Title
+	}
+
+	renderPage(c, map[string]interface{}{
+		"title":           results.Night.Title,
+		"page":            "night",
+		"night":           results.Night,
+		"results":         results,
+		"candidates":      candidates,
+		"candidateTitles": titles,
+		"ranks":           nightRanks(len(results.Night.MovieIDs)),
+		"ballot":          ballot,
+		"winner":          winner,
+		"isOwner":         nightStore.IsOwner(id, voter),
+		"shareURL":        baseURL(c) + localePath(locale, nightPath(id)),
+		"meta": pageMeta{
+			Title:       results.Night.Title,
+			Description: i18n.T(locale, "page.night.share_description"),
+		},
+	})
+}
+
+// nightInput — тело запроса создания голосования
+type nightInput struct {
+	Title    string    `json:"title"`
+	Method   string    `json:"method"`
+	MovieIDs []string  `json:"movieIds"`
+	Deadline time.Time `json:"deadline"`
+}
+
+// ballotInput — тело запроса голосования
+type ballotInput struct {
+	Name    string   `json:"name,omitempty"`
+	Choices []string `json:"choices"`
+}
+
+// failNight отвечает ошибкой хранилища голосований
+func failNight(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, movienight.ErrNotFound):
+		api.Fail(c, http.StatusNotFound, api.CodeNightNotFound)
+	case errors.Is(err, movienight.ErrClosed):
+		api.Fail(c, http.StatusConflict, api.CodeNightClosed)
+	case errors.Is(err, movienight.ErrNotOwner):
+		api.Fail(c, http.StatusForbidden, api.CodeNotNightOwner)
+	case errors.Is(err, movienight.ErrInvalid):
+		api.FailDetails(c, http.StatusUnprocessableEntity, api.CodeValidationFailed, strings.ReplaceAll(err.Error(), "\n", "; "))
+	default:
+		c.Error(err)
+		api.Fail(c, http.StatusInternalServerError, api.CodeInternal)
+	}
+}
+
+// Обработчик API v1 для создания голосования. Создатель получает cookie visitor,
+// по которой потом может завершить голосование досрочно.
+func handleV1CreateNight(c *gin.Context) {
+	var input nightInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		api.FailDetails(c, http.StatusBadRequest, api.CodeBadRequest, err.Error())
+		return
+	}
+	var unknown []string
+	for _, id := range input.MovieIDs {
+		if _, ok := movieCatalog.Movie(id); !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		api.FailDetails(c, http.StatusUnprocessableEntity, api.CodeValidationFailed, "нет в каталоге: "+strings.Join(unknown, ", "))
+		return
+	}
+	owner, err := ensureVisitor(c)
+	if err != nil {
+		failNight(c, err)
+		return
+	}
+
+	night, err := nightStore.Create(owner, models.MovieNight{
+		Title:    input.Title,
+		Method:   input.Method,
+		MovieIDs: input.MovieIDs,
+		Deadline: input.Deadline,
+	})
+	if err != nil {
+		failNight(c, err)
+		return
+	}
+	c.Header("Location", apiV1Prefix+"/nights/"+url.PathEscape(night.ID))
+	api.Respond(c, http.StatusCreated, api.Response{Data: night, Meta: itemMeta()})
+}
+
+// Обработчик API v1 для текущих итогов голосования
+func handleV1Night(c *gin.Context) {
+	results, ok := nightStore.Results(c.Param("id"))
+	if !ok {
+		api.Fail(c, http.StatusNotFound, api.CodeNightNotFound)
+		return
+	}
+	api.OK(c, results, itemMeta())
+}
+
+// Обработчик API v1 для голосования: бюллетень зрителя из cookie visitor
+// заменяет его прежний бюллетень
+func handleV1VoteNight(c *gin.Context) {
+	var input ballotInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		api.FailDetails(c, http.StatusBadRequest, api.CodeBadRequest, err.Error())
+		return
+	}
+	voter, err := ensureVisitor(c)
+	if err != nil {
+		failNight(c, err)
+		return
+	}
+	results, err := nightStore.Vote(c.Param("id"), voter, models.NightBallot{Name: input.Name, Choices: input.Choices})
+	if err != nil {
+		failNight(c, err)
+		return
+	}
+	api.OK(c, results, itemMeta())
+}
+
+// Обработчик API v1 для досрочного завершения голосования его создателем
+func handleV1CloseNight(c *gin.Context) {
+	results, err := nightStore.Close(c.Param("id"), visitorID(c))
+	if err != nil {
+		failNight(c, err)
+		return
+	}
+	api.OK(c, results, itemMeta())
+}
+
+// Обработчик API v1, транслирующий итоги голосования по Server-Sent Events:
+// сначала текущее состояние, затем night.updated после каждого голоса
+// и night.closed с победителем
+func handleV1NightEvents(c *gin.Context) {
+	results, stream, unsubscribe, ok := subscribeNight(c.Param("id"))
+	if !ok {
+		api.Fail(c, http.StatusNotFound, api.CodeNightNotFound)
+		return
+	}
+	if stream != nil {
+		defer unsubscribe()
+	} else {
+		// Голосование завершено: отдаём итоги с night.closed и закрываем поток
+		finished := make(chan events.Event)
+		close(finished)
+		stream = finished
+	}
+	streamEvents(c, stream, sse.Event{Event: nightEventType(results.Night), Data: results})
+}
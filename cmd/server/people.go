@@ -0,0 +1,106 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/api"
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/middleware"
+	"movie-catalog/internal/models"
+)
+
+// personPath возвращает адрес страницы человека
+func personPath(id string) string {
+	return "/person/" + url.PathEscape(id)
+}
+
+// personLink — имя в сведениях о фильме; URL пустой, если человека нет в people.json
+type personLink struct {
+	Name string
+	URL  string
+}
+
+// personLinks возвращает имена из names со ссылками на страницы тех, кто есть в people.json.
+// Имена известных людей переводятся на язык locale.
+func personLinks(locale string, movie models.Movie, names []string, role string) []personLink {
+	ids := make(map[string]string)
+	for _, credit := range movie.Credits {
+		if credit.Role == role {
+			ids[credit.Name] = credit.PersonID
+		}
+	}
+
+	links := make([]personLink, 0, len(names))
+	for _, name := range names {
+		link := personLink{Name: name}
+		if person, ok := movieCatalog.Person(ids[name]); ok {
+			link.Name = person.Localized(locale).Name
+			link.URL = localePath(locale, personPath(person.ID))
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// personDetails собирает человека и его фильмы на языке locale
+func personDetails(id, locale string) (models.PersonDetails, bool) {
+	person, ok := movieCatalog.Person(id)
+	if !ok {
+		return models.PersonDetails{}, false
+	}
+	movies := movieCatalog.PersonMovies(id)
+	for i := range movies {
+		movies[i].Movie = movies[i].Movie.Localized(locale)
+	}
+	return models.PersonDetails{Person: person.Localized(locale), Movies: movies}, true
+}
+
+// Обработчик страницы человека: фото, имя и фильмы каталога с его участием
+func handlePerson(c *gin.Context) {
+	locale := middleware.Locale(c)
+	details, ok := personDetails(c.Param("id"), locale)
+	if !ok {
+		c.String(http.StatusNotFound, i18n.T(locale, "error.person_not_found"))
+		return
+	}
+
+	meta := pageMeta{
+		Title:       details.Name,
+		Description: i18n.T(locale, "page.person.description", details.Name, len(details.Movies)),
+		URL:         baseURL(c) + localePath(locale, personPath(details.ID)),
+		Type:        "profile",
+	}
+	if details.Photo != "" {
+		meta.Image = absoluteURL(c, details.Photo)
+	}
+
+	renderPage(c, map[string]interface{}{
+		"title":  details.Name,
+		"page":   "person",
+		"person": details,
+		"meta":   meta,
+	})
+}
+
+// Обработчик API v1 для человека и его фильмов в каталоге
+func handleV1Person(c *gin.Context) {
+	details, ok := personDetails(c.Param("id"), api.Locale(c))
+	if !ok {
+		api.Fail(c, http.StatusNotFound, api.CodePersonNotFound)
+		return
+	}
+	api.OK(c, details, itemMeta())
+}
+
+// Обработчик API для человека и его фильмов в каталоге
+func handleAPIPerson(c *gin.Context) {
+	details, ok := personDetails(c.Param("id"), i18n.Default)
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Человек не найден"})
+		return
+	}
+	c.JSON(http.StatusOK, details)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/api"
+	"movie-catalog/internal/catalog"
+	"movie-catalog/internal/models"
+)
+
+// randomQuery собирает условия случайного выбора из параметров запроса:
+// category, yearFrom, maxRuntime (минуты), includeUnknownRuntime и excludeWatched —
+// не предлагать фильмы, которые зритель уже оценил. Продолжительность известна
+// не у всех фильмов каталога, поэтому по умолчанию фильмы без неё проходят
+// ограничение maxRuntime: иначе выбор сводился бы к немногим фильмам.
+func randomQuery(c *gin.Context) (q catalog.Query, excludeWatched bool, ok bool) {
+	q.Category = c.Query("category")
+	q.UnknownRuntime = true
+	for name, dst := range map[string]*int{
+		"yearFrom":   &q.YearFrom,
+		"maxRuntime": &q.RuntimeTo,
+	} {
+		if value := c.Query(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return q, false, false
+			}
+			*dst = n
+		}
+	}
+	if value := c.Query("includeUnknownRuntime"); value != "" {
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			return q, false, false
+		}
+		q.UnknownRuntime = include
+	}
+	if value := c.Query("excludeWatched"); value != "" {
+		exclude, err := strconv.ParseBool(value)
+		if err != nil {
+			return q, false, false
+		}
+		excludeWatched = exclude
+	}
+	return q, excludeWatched, true
+}
+
+// pickRandomMovie выбирает подходящий под q фильм, чаще — высоко оценённый зрителями.
+// При excludeWatched пропускаются фильмы, оценённые зрителем из cookie.
+func pickRandomMovie(c *gin.Context, q catalog.Query, excludeWatched bool) (models.Movie, bool) {
+	var watched map[string]bool
+	if userID := visitorID(c); excludeWatched && userID != "" {
+		watched = make(map[string]bool)
+		for _, r := range ratingStore.Ratings(userID) {
+			watched[r.MovieID] = true
+		}
+	}
+
+	var candidates []string
+	for _, movie := range movieCatalog.Find(q) {
+		if !watched[movie.ID] {
+			candidates = append(candidates, movie.ID)
+		}
+	}
+	movieID, ok := recommender.Model().Pick(candidates, rand.Float64)
+	if !ok {
+		return models.Movie{}, false
+	}
+	return movieCatalog.Movie(movieID)
+}
+
+// Обработчик API v1 для случайного фильма на вечер
+func handleV1RandomMovie(c *gin.Context) {
+	q, excludeWatched, ok := randomQuery(c)
+	if !ok {
+		api.Fail(c, http.StatusBadRequest, api.CodeBadRequest)
+		return
+	}
+	if q.Category != "" {
+		if _, ok := movieCatalog.Category(q.Category); !ok {
+			api.Fail(c, http.StatusNotFound, api.CodeCategoryNotFound)
+			return
+		}
+	}
+	movie, ok := pickRandomMovie(c, q, excludeWatched)
+	if !ok {
+		api.Fail(c, http.StatusNotFound, api.CodeNoMatchingMovies)
+		return
+	}
+	api.OK(c, movie.Localized(api.Locale(c)), itemMeta())
+}
+
+// Обработчик API для случайного фильма на вечер
+func handleAPIRandomMovie(c *gin.Context) {
+	q, excludeWatched, ok := randomQuery(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Некорректные параметры запроса"})
+		return
+	}
+	movie, ok := pickRandomMovie(c, q, excludeWatched)
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Нет фильмов с такими условиями"})
+		return
+	}
+	c.JSON(http.StatusOK, movie)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/middleware"
+	"movie-catalog/internal/models"
+	"movie-catalog/internal/sitemap"
+)
+
+// pageMeta — данные для тегов Open Graph и Twitter Cards в base.html
+type pageMeta struct {
+	Title       string
+	Description string
+	// URL — канонический абсолютный адрес страницы
+	URL string
+	// Image — абсолютный адрес картинки превью, необязательно
+	Image string
+	// Type — тип объекта Open Graph: website или video.movie
+	Type string
+}
+
+// TwitterCard возвращает тип карточки Twitter: крупная картинка, если она есть
+func (m pageMeta) TwitterCard() string {
+	if m.Image != "" {
+		return "summary_large_image"
+	}
+	return "summary"
+}
+
+// withPageMeta дополняет данные страницы метаданными превью, если обработчик
+// не задал их сам
+func withPageMeta(c *gin.Context, data map[string]interface{}) {
+	locale := middleware.Locale(c)
+	meta, _ := data["meta"].(pageMeta)
+	if meta.Title == "" {
+		meta.Title, _ = data["title"].(string)
+	}
+	if meta.Description == "" {
+		meta.Description = i18n.T(locale, "site.description")
+	}
+	if meta.URL == "" {
+		meta.URL = baseURL(c) + c.Request.URL.EscapedPath()
+	}
+	if meta.Type == "" {
+		meta.Type = "website"
+	}
+	data["meta"] = meta
+	data["siteName"] = i18n.T(locale, "site.name")
+}
+
+// moviePageMeta возвращает метаданные превью страницы фильма
+func moviePageMeta(c *gin.Context, movie models.Movie) pageMeta {
+	meta := pageMeta{
+		Title:       movieHeading(movie),
+		Description: movie.Description,
+		URL:         baseURL(c) + localePath(middleware.Locale(c), moviePath(movie.ID)),
+		Type:        "video.movie",
+	}
+	if movie.ImagePath != "" {
+		meta.Image = absoluteURL(c, movie.ImagePath)
+	}
+	return meta
+}
+
+// movieJSONLD возвращает разметку schema.org/Movie для страницы фильма
+func movieJSONLD(c *gin.Context, movie models.Movie) map[string]interface{} {
+	locale := middleware.Locale(c)
+	ld := map[string]interface{}{
+		"@context":      "https://schema.org",
+		"@type":         "Movie",
+		"name":          movie.Title,
+		"url":           baseURL(c) + localePath(locale, moviePath(movie.ID)),
+		"datePublished": fmt.Sprint(movie.Year),
+		"genre":         i18n.CategoryName(locale, movie.Category),
+		"inLanguage":    locale,
+	}
+	if description := movie.FullDescription; description != "" {
+		ld["description"] = description
+	} else if movie.Description != "" {
+		ld["description"] = movie.Description
+	}
+	if movie.ImagePath != "" {
+		ld["image"] = absoluteURL(c, movie.ImagePath)
+	}
+	if movie.Link != "" {
+		ld["sameAs"] = movie.Link
+	}
+	return ld
+}
+
+// Обработчик карты сайта: главная, список фильмов, категории, страницы фильмов, людей, меток и подборок на всех языках
+func handleSitemap(c *gin.Context) {
+	base := baseURL(c)
+	var urls []sitemap.URL
+	for _, locale := range i18n.Supported {
+		prefix := base + middleware.LocalePrefix(locale)
+		urls = append(urls,
+			sitemap.URL{Loc: prefix + "/", Priority: 1},
+			sitemap.URL{Loc: prefix + "/movies", Priority: 0.9},
+		)
+		for _, category := range movieCatalog.Categories() {
+			urls = append(urls, sitemap.URL{Loc: prefix + "/category/" + url.PathEscape(category), Priority: 0.7})
+		}
+		for _, movie := range movieCatalog.All() {
+			urls = append(urls, sitemap.URL{Loc: prefix + moviePath(movie.ID), LastMod: movie.CreatedAt, Priority: 0.5})
+		}
+		for _, person := range movieCatalog.People() {
+			urls = append(urls, sitemap.URL{Loc: prefix + personPath(person.ID), Priority: 0.4})
+		}
+		for _, tag := range tagList(locale) {
+			urls = append(urls, sitemap.URL{Loc: prefix + tagPath(tag.Key), Priority: 0.5})
+		}
+		for _, collection := range collectionStore.List() {
+			urls = append(urls, sitemap.URL{Loc: prefix + collectionPath(collection.Slug), LastMod: collection.UpdatedAt, Priority: 0.6})
+		}
+	}
+
+	body, err := sitemap.Marshal(urls)
+	if err != nil {
+		c.Error(err)
+		c.String(http.StatusInternalServerError, "Ошибка формирования карты сайта")
+		return
+	}
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
+
+// Обработчик robots.txt: индексировать можно всё, кроме API, служебных страниц
+// и голосований, ссылки на которые предназначены только участникам
+func handleRobots(c *gin.Context) {
+	lines := []string{
+		"User-agent: *",
+		"Disallow: /api/",
+		"Disallow: /graphql",
+		"Disallow: /graphiql",
+		"Disallow: /csp-report",
+	}
+	for _, locale := range i18n.Supported {
+		lines = append(lines, "Disallow: "+middleware.LocalePrefix(locale)+"/night/")
+	}
+	lines = append(lines,
+		"Allow: /",
+		"",
+		"Sitemap: "+baseURL(c)+"/sitemap.xml",
+	)
+	c.String(http.StatusOK, strings.Join(lines, "\n")+"\n")
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/api"
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/models"
+	"movie-catalog/internal/similar"
+)
+
+// Количество похожих фильмов по умолчанию и наибольшее, которое можно запросить
+const (
+	defaultSimilarLimit = 6
+	maxSimilarLimit     = 20
+)
+
+// Индекс похожих фильмов; перестраивается после каждой успешной перезагрузки каталога
+var similarIndex atomic.Pointer[similar.Index]
+
+// rebuildSimilarIndex перестраивает индекс похожих фильмов по текущему каталогу
+func rebuildSimilarIndex(err error) {
+	if err == nil {
+		similarIndex.Store(similar.Build(movieCatalog.All()))
+	}
+}
+
+// similarMovies возвращает фильмы, похожие на фильм id, на языке locale;
+// false — фильма нет в каталоге
+func similarMovies(id, locale string, limit int) ([]models.SimilarMovie, bool) {
+	index := similarIndex.Load()
+	if index == nil {
+		return nil, false
+	}
+	movies, ok := index.Similar(id, limit)
+	for i := range movies {
+		movies[i].Movie = movies[i].Movie.Localized(locale)
+	}
+	return movies, ok
+}
+
+// queryLimit читает параметр limit: от 1 до maxLimit, по умолчанию fallback
+func queryLimit(c *gin.Context, fallback, maxLimit int) (int, bool) {
+	value := c.Query("limit")
+	if value == "" {
+		return fallback, true
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxLimit {
+		return 0, false
+	}
+	return limit, true
+}
+
+// Обработчик API v1 для фильмов, похожих на данный
+func handleV1SimilarMovies(c *gin.Context) {
+	limit, ok := queryLimit(c, defaultSimilarLimit, maxSimilarLimit)
+	if !ok {
+		api.Fail(c, http.StatusBadRequest, api.CodeBadRequest)
+		return
+	}
+	movies, ok := similarMovies(c.Param("id"), api.Locale(c), limit)
+	if !ok {
+		api.Fail(c, http.StatusNotFound, api.CodeMovieNotFound)
+		return
+	}
+	api.OK(c, movies, listMeta(len(movies)))
+}
+
+// Обработчик API для фильмов, похожих на данный
+func handleAPISimilarMovies(c *gin.Context) {
+	limit, ok := queryLimit(c, defaultSimilarLimit, maxSimilarLimit)
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Некорректный параметр limit"})
+		return
+	}
+	movies, ok := similarMovies(c.Param("id"), i18n.Default, limit)
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Фильм не найден"})
+		return
+	}
+	c.JSON(http.StatusOK, movies)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/api"
+	"movie-catalog/internal/catalog"
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/middleware"
+	"movie-catalog/internal/models"
+)
+
+// Количество ступеней размера шрифта в облаке меток (классы tag-size-1…5)
+const tagCloudSizes = 5
+
+// tagPath возвращает адрес страницы метки
+func tagPath(tag string) string {
+	return "/tag/" + url.PathEscape(tag)
+}
+
+// tagList собирает метки с количеством фильмов и названиями на языке locale:
+// сначала самые частые, при равенстве — по алфавиту ключей
+func tagList(locale string) []models.Tag {
+	counts := movieCatalog.Tags()
+	tags := make([]models.Tag, 0, len(counts))
+	for key, count := range counts {
+		tags = append(tags, models.Tag{Key: key, Name: i18n.TagName(locale, key), Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Key < tags[j].Key
+	})
+	return tags
+}
+
+// tagLink — ссылка на страницу метки; Size — ступень размера в облаке меток
+type tagLink struct {
+	Name  string
+	URL   string
+	Count int
+	Size  int
+}
+
+// tagLinks возвращает ссылки на страницы меток фильма
+func tagLinks(locale string, tags []string) []tagLink {
+	links := make([]tagLink, 0, len(tags))
+	for _, tag := range tags {
+		links = append(links, tagLink{Name: i18n.TagName(locale, tag), URL: localePath(locale, tagPath(tag))})
+	}
+	return links
+}
+
+// tagCloud возвращает облако меток для главной страницы: метки по алфавиту названий,
+// размер пропорционален количеству фильмов
+func tagCloud(locale string) []tagLink {
+	tags := tagList(locale)
+	if len(tags) == 0 {
+		return nil
+	}
+	largest := tags[0].Count
+
+	cloud := make([]tagLink, 0, len(tags))
+	for _, tag := range tags {
+		cloud = append(cloud, tagLink{
+			Name:  tag.Name,
+			URL:   localePath(locale, tagPath(tag.Key)),
+			Count: tag.Count,
+			Size:  1 + (tag.Count-1)*(tagCloudSizes-1)/max(largest-1, 1),
+		})
+	}
+	sort.Slice(cloud, func(i, j int) bool { return cloud[i].Name < cloud[j].Name })
+	return cloud
+}
+
+// queryTags собирает метки из параметра tags: через запятую или повтором параметра
+func queryTags(c *gin.Context) []string {
+	var tags []string
+	for _, value := range c.QueryArray("tags") {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// Обработчик страницы метки со всеми её фильмами
+func handleTag(c *gin.Context) {
+	locale := middleware.Locale(c)
+	tag := c.Param("tag")
+	movies := movieCatalog.Find(catalog.Query{Tags: []string{tag}})
+	if len(movies) == 0 {
+		c.String(http.StatusNotFound, i18n.T(locale, "error.tag_not_found"))
+		return
+	}
+	name := i18n.TagName(locale, tag)
+
+	renderPage(c, map[string]interface{}{
+		"title":  name,
+		"page":   "tag",
+		"tag":    name,
+		"movies": localizeMovies(movies, locale),
+		"meta": pageMeta{
+			Title:       name,
+			Description: i18n.T(locale, "page.tag.description", name, len(movies)),
+			URL:         baseURL(c) + localePath(locale, tagPath(tag)),
+		},
+	})
+}
+
+// Обработчик API v1 для списка меток с количеством фильмов
+func handleV1Tags(c *gin.Context) {
+	tags := tagList(api.Locale(c))
+	api.OK(c, tags, listMeta(len(tags)))
+}
+
+// Обработчик API для списка меток с количеством фильмов
+func handleAPITags(c *gin.Context) {
+	c.JSON(http.StatusOK, tagList(i18n.Default))
+}
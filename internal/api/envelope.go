@@ -0,0 +1,104 @@
+// Package api реализует общий формат ответов API v1: конверт с данными или ошибкой,
+// машиночитаемые коды ошибок с локализованными сообщениями и выбор формата по Accept
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+)
+
+// Response — успешный ответ API v1
+type Response struct {
+	Data interface{} `json:"data"`
+	Meta *Meta       `json:"meta,omitempty"`
+}
+
+// Meta — сведения об ответе
+type Meta struct {
+	// Count — количество элементов в data, если это список
+	Count int `json:"count"`
+	// CatalogVersion — версия каталога, из которой получены данные
+	CatalogVersion string `json:"catalogVersion"`
+}
+
+// ErrorResponse — ответ API v1 с ошибкой
+type ErrorResponse struct {
+	Error Error `json:"error"`
+}
+
+// Error — ошибка с машиночитаемым кодом и сообщением на языке клиента
+type Error struct {
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+	// Details — подробности, например список ошибок проверки; не переводятся
+	Details string `json:"details,omitempty"`
+}
+
+// Форматы ответа в порядке предпочтения; первый используется, если Accept не задан
+var offeredFormats = []string{binding.MIMEJSON, binding.MIMEYAML2, binding.MIMEYAML}
+
+// OK отвечает 200 с данными в конверте
+func OK(c *gin.Context, data interface{}, meta *Meta) {
+	Respond(c, http.StatusOK, Response{Data: data, Meta: meta})
+}
+
+// Fail отвечает ошибкой с кодом code и сообщением на языке из Accept-Language
+func Fail(c *gin.Context, status int, code Code) {
+	Respond(c, status, ErrorResponse{Error: Error{Code: code, Message: code.Message(Locale(c))}})
+}
+
+// FailDetails отвечает ошибкой как Fail, добавляя подробности details
+func FailDetails(c *gin.Context, status int, code Code, details string) {
+	Respond(c, status, ErrorResponse{Error: Error{Code: code, Message: code.Message(Locale(c)), Details: details}})
+}
+
+// Respond отдаёт payload в формате, выбранном по заголовку Accept (JSON или YAML).
+// Если ни один формат не подходит, отвечает 406 в JSON.
+func Respond(c *gin.Context, status int, payload interface{}) {
+	// Vary дополняется: middleware уже могли добавить Cookie и Accept-Encoding
+	addVary(c.Writer.Header(), "Accept")
+	addVary(c.Writer.Header(), "Accept-Language")
+
+	switch c.NegotiateFormat(offeredFormats...) {
+	case binding.MIMEJSON:
+		c.JSON(status, payload)
+	case binding.MIMEYAML, binding.MIMEYAML2:
+		// YAML строится из JSON-представления, чтобы имена полей совпадали с json-тегами
+		generic, err := toGeneric(payload)
+		if err != nil {
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: Error{Code: CodeInternal, Message: CodeInternal.Message(Locale(c))}})
+			return
+		}
+		c.Render(status, render.YAML{Data: generic})
+	default:
+		c.JSON(http.StatusNotAcceptable, ErrorResponse{Error: Error{Code: CodeNotAcceptable, Message: CodeNotAcceptable.Message(Locale(c))}})
+	}
+}
+
+func toGeneric(payload interface{}) (interface{}, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	err = json.Unmarshal(data, &generic)
+	return generic, err
+}
+
+// addVary добавляет значение в заголовок Vary, не дублируя его
+func addVary(header http.Header, value string) {
+	for _, v := range header.Values("Vary") {
+		for _, item := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(item), value) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", value)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/i18n"
+)
+
+// Code — машиночитаемый код ошибки API
+type Code string
+
+// Коды ошибок API v1
+const (
+	CodeMovieNotFound      Code = "movie_not_found"
+	CodeCategoryNotFound   Code = "category_not_found"
+	CodeNoMatchingMovies   Code = "no_matching_movies"
+	CodePersonNotFound     Code = "person_not_found"
+	CodeCollectionNotFound Code = "collection_not_found"
+	CodeCollectionExists   Code = "collection_exists"
+	CodeNightNotFound      Code = "night_not_found"
+	CodeNightClosed        Code = "night_closed"
+	CodeNotNightOwner      Code = "not_night_owner"
+	CodeRevisionNotFound   Code = "revision_not_found"
+	CodeValidationFailed   Code = "validation_failed"
+	CodeUnauthorized       Code = "unauthorized"
+	CodeWriteDisabled      Code = "write_disabled"
+	CodeUnknownProvider    Code = "unknown_provider"
+	CodeRouteNotFound      Code = "route_not_found"
+	CodeRateLimited        Code = "rate_limited"
+	CodeNotAcceptable      Code = "not_acceptable"
+	CodeBadRequest         Code = "bad_request"
+	CodeInternal           Code = "internal_error"
+)
+
+// Codes возвращает все коды ошибок, например для описания в спецификации
+func Codes() []string {
+	return []string{
+		string(CodeMovieNotFound),
+		string(CodeCategoryNotFound),
+		string(CodeNoMatchingMovies),
+		string(CodePersonNotFound),
+		string(CodeCollectionNotFound),
+		string(CodeCollectionExists),
+		string(CodeNightNotFound),
+		string(CodeNightClosed),
+		string(CodeNotNightOwner),
+		string(CodeRevisionNotFound),
+		string(CodeValidationFailed),
+		string(CodeUnauthorized),
+		string(CodeWriteDisabled),
+		string(CodeUnknownProvider),
+		string(CodeRouteNotFound),
+		string(CodeRateLimited),
+		string(CodeNotAcceptable),
+		string(CodeBadRequest),
+		string(CodeInternal),
+	}
+}
+
+// Message возвращает сообщение об ошибке на языке locale с откатом на русский
+func (code Code) Message(locale string) string {
+	if text, ok := i18n.Lookup(locale, "error."+string(code)); ok {
+		return text
+	}
+	return string(code)
+}
+
+// Locale возвращает язык запроса, выбранный middleware.DetectLocale,
+// а без него — язык из заголовка Accept-Language
+func Locale(c *gin.Context) string {
+	if locale := i18n.FromContext(c.Request.Context()); locale != "" {
+		return locale
+	}
+	return i18n.Match(c.GetHeader("Accept-Language"))
+}
@@ -0,0 +1,415 @@
+// Package catalog загружает каталог фильмов и людей из JSON-файлов и держит его в памяти
+package catalog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"movie-catalog/internal/models"
+)
+
+// Catalog — потокобезопасное хранилище фильмов, загруженных из movies.json,
+// и людей из необязательного people.json в том же каталоге.
+// Файлы перечитываются при изменении (см. Watch) или по явному вызову Reload.
+type Catalog struct {
+	path       string
+	peoplePath string
+
+	mu         sync.RWMutex
+	raw        []byte
+	version    string
+	modTime    time.Time
+	byCategory map[string][]models.Movie
+	byID       map[string]models.Movie
+	byExternal map[string]string
+	people     map[string]models.Person
+	byPerson   map[string][]string
+	byTag      map[string]int
+	imageHosts []string
+
+	hooksMu     sync.Mutex
+	hooks       []func(error)
+	changeHooks []func(Diff)
+}
+
+// Diff — изменения каталога после перезагрузки
+type Diff struct {
+	Added   []models.Movie
+	Updated []models.Movie
+	Removed []models.Movie
+	// Version — версия каталога после перезагрузки
+	Version string
+}
+
+// Empty сообщает, что фильмы не изменились
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Updated) == 0 && len(d.Removed) == 0
+}
+
+// New создаёт каталог для файла path; люди читаются из people.json рядом с ним.
+// Данные загружаются вызовом Reload.
+func New(path string) *Catalog {
+	return &Catalog{
+		path:       path,
+		peoplePath: filepath.Join(filepath.Dir(path), "people.json"),
+		byCategory: make(map[string][]models.Movie),
+		byID:       make(map[string]models.Movie),
+		byExternal: make(map[string]string),
+		people:     make(map[string]models.Person),
+		byPerson:   make(map[string][]string),
+		byTag:      make(map[string]int),
+	}
+}
+
+// Path возвращает путь к файлу каталога
+func (c *Catalog) Path() string {
+	return c.path
+}
+
+// OnReload регистрирует функцию, вызываемую после каждой попытки перезагрузки.
+// err равен nil при успешной загрузке.
+func (c *Catalog) OnReload(hook func(err error)) {
+	c.hooksMu.Lock()
+	defer c.hooksMu.Unlock()
+	c.hooks = append(c.hooks, hook)
+}
+
+// OnChange регистрирует функцию, вызываемую после успешной перезагрузки
+// с перечнем добавленных, изменённых и удалённых фильмов
+func (c *Catalog) OnChange(hook func(diff Diff)) {
+	c.hooksMu.Lock()
+	defer c.hooksMu.Unlock()
+	c.changeHooks = append(c.changeHooks, hook)
+}
+
+// Reload перечитывает и проверяет файлы каталога (см. Validate и ValidatePeople).
+// При ошибке остаются прежние данные.
+func (c *Catalog) Reload() error {
+	diff, err := c.load()
+	c.notify(err)
+	if err == nil {
+		c.notifyChange(diff)
+	}
+	return err
+}
+
+func (c *Catalog) load() (Diff, error) {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return Diff{}, fmt.Errorf("не удалось прочитать файл каталога: %w", err)
+	}
+	raw, err := os.ReadFile(c.path)
+	if err != nil {
+		return Diff{}, fmt.Errorf("не удалось прочитать файл каталога: %w", err)
+	}
+
+	var byCategory map[string][]models.Movie
+	if err := json.Unmarshal(raw, &byCategory); err != nil {
+		return Diff{}, fmt.Errorf("не удалось разобрать файл каталога: %w", err)
+	}
+	if err := Validate(byCategory); err != nil {
+		return Diff{}, fmt.Errorf("каталог не прошёл проверку: %w", err)
+	}
+	people, peopleRaw, peopleModTime, err := readPeople(c.peoplePath)
+	if err != nil {
+		return Diff{}, err
+	}
+	if err := ValidatePeople(people); err != nil {
+		return Diff{}, fmt.Errorf("список людей не прошёл проверку: %w", err)
+	}
+	peopleByID, peopleByName := indexPeople(people)
+	modTime := info.ModTime()
+	if peopleModTime.After(modTime) {
+		modTime = peopleModTime
+	}
+
+	c.mu.RLock()
+	previous := c.byID
+	c.mu.RUnlock()
+
+	byID := make(map[string]models.Movie)
+	byExternal := make(map[string]string)
+	byPerson := make(map[string][]string)
+	byTag := make(map[string]int)
+	hosts := make(map[string]bool)
+	for _, movies := range byCategory {
+		for i := range movies {
+			movie := &movies[i]
+			// Фильмам без даты добавления достаётся дата из прошлой загрузки,
+			// а новым — время изменения файла, то есть момент их появления в каталоге
+			if movie.CreatedAt.IsZero() {
+				if old, ok := previous[movie.ID]; ok {
+					movie.CreatedAt = old.CreatedAt
+				} else {
+					movie.CreatedAt = info.ModTime().UTC()
+				}
+			}
+			// Ошибки уже отсеяны Validate
+			movie.ExternalIDs, _ = externalIDs(*movie)
+			for provider, id := range movie.ExternalIDs {
+				byExternal[externalKey(provider, id)] = movie.ID
+			}
+			movie.Credits = credits(*movie, peopleByName)
+			for _, credit := range movie.Credits {
+				if ids := byPerson[credit.PersonID]; len(ids) == 0 || ids[len(ids)-1] != movie.ID {
+					byPerson[credit.PersonID] = append(ids, movie.ID)
+				}
+			}
+			for _, tag := range movie.Tags {
+				byTag[tag]++
+			}
+			byID[movie.ID] = *movie
+			if u, err := url.Parse(movie.ImagePath); err == nil && u.Host != "" {
+				hosts[u.Scheme+"://"+u.Host] = true
+			}
+		}
+	}
+	imageHosts := make([]string, 0, len(hosts))
+	for host := range hosts {
+		imageHosts = append(imageHosts, host)
+	}
+	sort.Strings(imageHosts)
+
+	sum := sha256.Sum256(append(append([]byte(nil), raw...), peopleRaw...))
+
+	c.mu.Lock()
+	diff := diffMovies(c.byID, byID)
+	diff.Version = hex.EncodeToString(sum[:6])
+	c.raw = raw
+	c.version = diff.Version
+	c.modTime = modTime
+	c.byCategory = byCategory
+	c.byID = byID
+	c.byExternal = byExternal
+	c.people = peopleByID
+	c.byPerson = byPerson
+	c.byTag = byTag
+	c.imageHosts = imageHosts
+	c.mu.Unlock()
+	return diff, nil
+}
+
+func (c *Catalog) notify(err error) {
+	c.hooksMu.Lock()
+	hooks := append([]func(error){}, c.hooks...)
+	c.hooksMu.Unlock()
+
+	for _, hook := range hooks {
+		hook(err)
+	}
+}
+
+func (c *Catalog) notifyChange(diff Diff) {
+	c.hooksMu.Lock()
+	hooks := append([]func(Diff){}, c.changeHooks...)
+	c.hooksMu.Unlock()
+
+	for _, hook := range hooks {
+		hook(diff)
+	}
+}
+
+// diffMovies сравнивает прежний и новый наборы фильмов по идентификаторам
+func diffMovies(before, after map[string]models.Movie) Diff {
+	var diff Diff
+	for id, movie := range after {
+		old, ok := before[id]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, movie)
+		case !reflect.DeepEqual(old, movie):
+			diff.Updated = append(diff.Updated, movie)
+		}
+	}
+	for id, movie := range before {
+		if _, ok := after[id]; !ok {
+			diff.Removed = append(diff.Removed, movie)
+		}
+	}
+
+	byID := func(movies []models.Movie) {
+		sort.Slice(movies, func(i, j int) bool { return movies[i].ID < movies[j].ID })
+	}
+	byID(diff.Added)
+	byID(diff.Updated)
+	byID(diff.Removed)
+	return diff
+}
+
+// Watch раз в interval проверяет время изменения movies.json и people.json и перезагружает
+// каталог, если один из файлов изменился. Повторная попытка для той же версии файла не делается,
+// чтобы ошибочный файл не вызывал перезагрузку на каждом тике.
+// Блокируется до отмены ctx.
+func (c *Catalog) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	c.mu.RLock()
+	lastSeen := c.modTime
+	c.mu.RUnlock()
+	statFailed := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			modTime, err := c.latestModTime()
+			if err != nil {
+				if !statFailed {
+					c.notify(fmt.Errorf("не удалось прочитать файл каталога: %w", err))
+				}
+				statFailed = true
+				continue
+			}
+			statFailed = false
+			if !modTime.Equal(lastSeen) {
+				lastSeen = modTime
+				c.Reload()
+			}
+		}
+	}
+}
+
+// latestModTime возвращает время изменения самого свежего из файлов каталога;
+// отсутствие people.json ошибкой не считается
+func (c *Catalog) latestModTime() (time.Time, error) {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	modTime := info.ModTime()
+	if people, err := os.Stat(c.peoplePath); err == nil && people.ModTime().After(modTime) {
+		modTime = people.ModTime()
+	}
+	return modTime, nil
+}
+
+// Loaded сообщает, был ли каталог хотя бы раз успешно загружен
+func (c *Catalog) Loaded() bool {
+	return c.Version() != ""
+}
+
+// Version возвращает версию загруженного каталога — короткий хеш содержимого файлов.
+// Пустая строка означает, что каталог ещё не загружен.
+func (c *Catalog) Version() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.version
+}
+
+// Count возвращает общее количество фильмов
+func (c *Catalog) Count() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.byID)
+}
+
+// Raw возвращает содержимое файла каталога в том виде, в каком оно было загружено
+func (c *Catalog) Raw() []byte {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.raw
+}
+
+// ByCategory возвращает фильмы, сгруппированные по ключам категорий
+func (c *Catalog) ByCategory() map[string][]models.Movie {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make(map[string][]models.Movie, len(c.byCategory))
+	for category, movies := range c.byCategory {
+		result[category] = append([]models.Movie(nil), movies...)
+	}
+	return result
+}
+
+// Category возвращает фильмы категории и признак её наличия
+func (c *Catalog) Category(category string) ([]models.Movie, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	movies, ok := c.byCategory[category]
+	return append([]models.Movie(nil), movies...), ok
+}
+
+// All возвращает все фильмы: категории по алфавиту, внутри категории — в порядке файла
+func (c *Catalog) All() []models.Movie {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	categories := make([]string, 0, len(c.byCategory))
+	for category := range c.byCategory {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	movies := make([]models.Movie, 0, len(c.byID))
+	for _, category := range categories {
+		movies = append(movies, c.byCategory[category]...)
+	}
+	return movies
+}
+
+// Categories возвращает отсортированный список ключей категорий
+func (c *Catalog) Categories() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	categories := make([]string, 0, len(c.byCategory))
+	for category := range c.byCategory {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// Movie ищет фильм по идентификатору
+func (c *Catalog) Movie(id string) (models.Movie, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	movie, ok := c.byID[id]
+	return movie, ok
+}
+
+// Tags возвращает количество фильмов с каждой меткой
+func (c *Catalog) Tags() map[string]int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tags := make(map[string]int, len(c.byTag))
+	for tag, count := range c.byTag {
+		tags[tag] = count
+	}
+	return tags
+}
+
+// ImageHosts возвращает источники (схема и хост) внешних постеров каталога
+func (c *Catalog) ImageHosts() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.imageHosts
+}
+
+// Sizes возвращает количество фильмов в каждой категории
+func (c *Catalog) Sizes() map[string]int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	sizes := make(map[string]int, len(c.byCategory))
+	for category, movies := range c.byCategory {
+		sizes[category] = len(movies)
+	}
+	return sizes
+}
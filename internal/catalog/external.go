@@ -0,0 +1,67 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"movie-catalog/internal/external"
+	"movie-catalog/internal/models"
+)
+
+// externalIDs возвращает внешние идентификаторы фильма: указанные явно
+// и извлечённые из ссылки Link, если сервиса ещё нет среди явных. Некорректные
+// идентификаторы пропускаются и возвращаются списком ошибок.
+func externalIDs(movie models.Movie) (map[string]string, []error) {
+	var errs []error
+	ids := make(map[string]string, len(movie.ExternalIDs)+1)
+	providers := make([]string, 0, len(movie.ExternalIDs))
+	for provider := range movie.ExternalIDs {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	for _, provider := range providers {
+		id := movie.ExternalIDs[provider]
+		if err := external.Validate(external.Provider(provider), id); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids[provider] = id
+	}
+
+	if movie.Link != "" {
+		provider, id, err := external.ParseURL(movie.Link)
+		switch {
+		case errors.Is(err, external.ErrUnknownURL):
+			// Ссылки на другие сайты допустимы, идентификатор из них не извлекается
+		case err != nil:
+			errs = append(errs, fmt.Errorf("ссылка %q: %w", movie.Link, err))
+		case ids[string(provider)] == "":
+			ids[string(provider)] = id
+		case ids[string(provider)] != id:
+			errs = append(errs, fmt.Errorf("ссылка %q не совпадает с идентификатором %s %q",
+				movie.Link, provider.Name(), ids[string(provider)]))
+		}
+	}
+
+	if len(ids) == 0 {
+		ids = nil
+	}
+	return ids, errs
+}
+
+// externalKey — ключ индекса фильмов по внешнему идентификатору
+func externalKey(provider, id string) string {
+	return provider + "/" + id
+}
+
+// ByExternalID возвращает фильм по идентификатору во внешнем сервисе
+func (c *Catalog) ByExternalID(provider, id string) (models.Movie, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	movieID, ok := c.byExternal[externalKey(provider, id)]
+	if !ok {
+		return models.Movie{}, false
+	}
+	return c.byID[movieID], true
+}
@@ -0,0 +1,33 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"movie-catalog/internal/jsonfile"
+	"movie-catalog/internal/models"
+)
+
+// ReadFile читает файл каталога без проверки и дополнения данных,
+// в том виде, в каком его редактируют утилиты
+func ReadFile(path string) (map[string][]models.Movie, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать файл каталога: %w", err)
+	}
+	var byCategory map[string][]models.Movie
+	if err := json.Unmarshal(data, &byCategory); err != nil {
+		return nil, fmt.Errorf("не удалось разобрать файл каталога: %w", err)
+	}
+	return byCategory, nil
+}
+
+// WriteFile записывает каталог с отступами, не экранируя HTML-символы.
+// Файл заменяется атомарно, чтобы сервер не прочитал его наполовину записанным.
+func WriteFile(path string, byCategory map[string][]models.Movie) error {
+	if err := jsonfile.Write(path, byCategory); err != nil {
+		return fmt.Errorf("не удалось записать каталог: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"movie-catalog/internal/models"
+)
+
+// readPeople читает список людей; отсутствие файла означает пустой список
+func readPeople(path string) ([]models.Person, []byte, time.Time, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf("не удалось прочитать список людей: %w", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf("не удалось прочитать список людей: %w", err)
+	}
+	var people []models.Person
+	if err := json.Unmarshal(raw, &people); err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf("не удалось разобрать список людей: %w", err)
+	}
+	return people, raw, info.ModTime(), nil
+}
+
+// ReadPeople читает people.json, например для проверки в catalog-lint.
+// Отсутствие файла не ошибка: список людей необязателен.
+func ReadPeople(path string) ([]models.Person, error) {
+	people, _, _, err := readPeople(path)
+	return people, err
+}
+
+// indexPeople строит указатели людей по идентификатору и по имени без учёта регистра
+func indexPeople(people []models.Person) (byID, byName map[string]models.Person) {
+	byID = make(map[string]models.Person, len(people))
+	byName = make(map[string]models.Person, len(people))
+	for _, person := range people {
+		byID[person.ID] = person
+		byName[strings.ToLower(person.Name)] = person
+	}
+	return byID, byName
+}
+
+// credits связывает режиссёров и актёров фильма с людьми из people.json по имени.
+// Имена, которых нет в списке людей, остаются без ссылки.
+func credits(movie models.Movie, byName map[string]models.Person) []models.Credit {
+	var result []models.Credit
+	add := func(names []string, role string) {
+		for _, name := range names {
+			if person, ok := byName[strings.ToLower(name)]; ok {
+				result = append(result, models.Credit{PersonID: person.ID, Name: name, Role: role})
+			}
+		}
+	}
+	add(movie.Directors, models.RoleDirector)
+	add(movie.Cast, models.RoleActor)
+	return result
+}
+
+// Person ищет человека по идентификатору
+func (c *Catalog) Person(id string) (models.Person, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	person, ok := c.people[id]
+	return person, ok
+}
+
+// People возвращает всех людей по алфавиту
+func (c *Catalog) People() []models.Person {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	people := make([]models.Person, 0, len(c.people))
+	for _, person := range c.people {
+		people = append(people, person)
+	}
+	sort.Slice(people, func(i, j int) bool { return people[i].Name < people[j].Name })
+	return people
+}
+
+// PersonMovies возвращает фильмы человека с его ролями, от новых к старым
+func (c *Catalog) PersonMovies(id string) []models.PersonMovie {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	movies := make([]models.PersonMovie, 0, len(c.byPerson[id]))
+	for _, movieID := range c.byPerson[id] {
+		movie := c.byID[movieID]
+		var roles []string
+		for _, credit := range movie.Credits {
+			if credit.PersonID == id {
+				roles = append(roles, credit.Role)
+			}
+		}
+		movies = append(movies, models.PersonMovie{Movie: movie, Roles: roles})
+	}
+	sort.SliceStable(movies, func(i, j int) bool {
+		if movies[i].Year != movies[j].Year {
+			return movies[i].Year > movies[j].Year
+		}
+		return movies[i].Title < movies[j].Title
+	})
+	return movies
+}
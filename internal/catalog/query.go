@@ -0,0 +1,166 @@
+package catalog
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"movie-catalog/internal/models"
+)
+
+// Query — условия выборки фильмов. Пустые поля не ограничивают выборку.
+type Query struct {
+	// Category — ключ категории
+	Category string
+	// YearFrom и YearTo — границы года выпуска включительно
+	YearFrom int
+	YearTo   int
+	// Text — подстрока для поиска в названиях, описаниях и именах без учёта регистра
+	Text string
+	// Director и Actor — часть имени режиссёра или актёра, например "Нолан"
+	Director string
+	Actor    string
+	// Country — страна производства без учёта регистра
+	Country string
+	// RuntimeFrom и RuntimeTo — границы продолжительности в минутах включительно.
+	// Фильмы без продолжительности при заданных границах не подходят, если не задан UnknownRuntime
+	RuntimeFrom int
+	RuntimeTo   int
+	// UnknownRuntime — фильмы без продолжительности проходят фильтр по ней
+	UnknownRuntime bool
+	// MaxAge — зритель этого возраста: подходят фильмы с ограничением не старше MaxAge+.
+	// Фильмы без ограничения при заданном MaxAge не подходят.
+	MaxAge *int
+	// Tags — метки фильма: по умолчанию нужны все, при AnyTag — хотя бы одна
+	Tags   []string
+	AnyTag bool
+}
+
+// Match сообщает, подходит ли фильм под условия
+func (q Query) Match(movie models.Movie) bool {
+	if q.Category != "" && movie.Category != q.Category {
+		return false
+	}
+	if q.YearFrom != 0 && movie.Year < q.YearFrom {
+		return false
+	}
+	if q.YearTo != 0 && movie.Year > q.YearTo {
+		return false
+	}
+	if q.Text != "" && textScore(movie, strings.ToLower(q.Text)) == 0 {
+		return false
+	}
+	if q.Director != "" && !containsName(movie.Directors, q.Director) {
+		return false
+	}
+	if q.Actor != "" && !containsName(movie.Cast, q.Actor) {
+		return false
+	}
+	if q.Country != "" && !containsFold(movie.Countries, q.Country) {
+		return false
+	}
+	if movie.Runtime == 0 {
+		if (q.RuntimeFrom != 0 || q.RuntimeTo != 0) && !q.UnknownRuntime {
+			return false
+		}
+	} else {
+		if q.RuntimeFrom != 0 && movie.Runtime < q.RuntimeFrom {
+			return false
+		}
+		if q.RuntimeTo != 0 && movie.Runtime > q.RuntimeTo {
+			return false
+		}
+	}
+	if q.MaxAge != nil {
+		if age, ok := movie.MinAge(); !ok || age > *q.MaxAge {
+			return false
+		}
+	}
+	if len(q.Tags) > 0 && !matchTags(movie.Tags, q.Tags, q.AnyTag) {
+		return false
+	}
+	return true
+}
+
+// matchTags сообщает, есть ли у фильма все метки из want, а при anyTag — хотя бы одна
+func matchTags(tags, want []string, anyTag bool) bool {
+	for _, tag := range want {
+		if slices.Contains(tags, tag) == anyTag {
+			return anyTag
+		}
+	}
+	return !anyTag
+}
+
+// containsName сообщает, есть ли в списке имя, содержащее part без учёта регистра
+func containsName(names []string, part string) bool {
+	part = strings.ToLower(part)
+	for _, name := range names {
+		if strings.Contains(strings.ToLower(name), part) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold сообщает, есть ли в списке значение, равное value без учёта регистра
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find возвращает фильмы, подходящие под условия, в порядке All.
+// При поиске по тексту выше оказываются совпадения в названии.
+func (c *Catalog) Find(q Query) []models.Movie {
+	var movies []models.Movie
+	for _, movie := range c.All() {
+		if q.Match(movie) {
+			movies = append(movies, movie)
+		}
+	}
+
+	if q.Text != "" {
+		text := strings.ToLower(q.Text)
+		sort.SliceStable(movies, func(i, j int) bool {
+			return textScore(movies[i], text) > textScore(movies[j], text)
+		})
+	}
+	return movies
+}
+
+// textScore оценивает совпадение текста: 3 — в названии или оригинальном названии,
+// 2 — в кратком описании, слогане или именах режиссёров и актёров, 1 — в полном описании,
+// 0 — совпадений нет
+func textScore(movie models.Movie, text string) int {
+	switch {
+	case strings.Contains(strings.ToLower(movie.Title), text),
+		strings.Contains(strings.ToLower(movie.OriginalTitle), text):
+		return 3
+	case strings.Contains(strings.ToLower(movie.Description), text),
+		strings.Contains(strings.ToLower(movie.Tagline), text),
+		containsName(movie.Directors, text),
+		containsName(movie.Cast, text):
+		return 2
+	case strings.Contains(strings.ToLower(movie.FullDescription), text):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Recent возвращает не больше limit последних добавленных фильмов,
+// при непустом category — только из этой категории
+func (c *Catalog) Recent(category string, limit int) []models.Movie {
+	movies := c.Find(Query{Category: category})
+	sort.SliceStable(movies, func(i, j int) bool {
+		return movies[i].CreatedAt.After(movies[j].CreatedAt)
+	})
+	if limit > 0 && len(movies) > limit {
+		movies = movies[:limit]
+	}
+	return movies
+}
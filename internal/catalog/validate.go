@@ -0,0 +1,128 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"movie-catalog/internal/i18n"
+	"movie-catalog/internal/models"
+)
+
+// Допустимый диапазон годов выпуска
+const (
+	minYear = 1888
+	maxYear = 2100
+)
+
+// tagPattern — формат метки: латиница в нижнем регистре, цифры и дефисы
+var tagPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Validate проверяет целостность каталога: непустые и уникальные идентификаторы,
+// наличие названий, корректные годы, продолжительность и возрастное ограничение,
+// формат меток, соответствие поля category ключу группы, формат и уникальность
+// внешних идентификаторов и языки переводов.
+// Возвращает все найденные ошибки сразу.
+func Validate(byCategory map[string][]models.Movie) error {
+	var errs []error
+	seen := make(map[string]string)
+	seenExternal := make(map[string]string)
+
+	categories := make([]string, 0, len(byCategory))
+	for category := range byCategory {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	for _, category := range categories {
+		for i, movie := range byCategory[category] {
+			where := fmt.Sprintf("%s[%d]", category, i)
+			if movie.ID == "" {
+				errs = append(errs, fmt.Errorf("%s: пустой id", where))
+			} else if other, ok := seen[movie.ID]; ok {
+				errs = append(errs, fmt.Errorf("%s: id %q уже используется в %s", where, movie.ID, other))
+			} else {
+				seen[movie.ID] = where
+			}
+			if movie.Title == "" {
+				errs = append(errs, fmt.Errorf("%s (%s): пустое название", where, movie.ID))
+			}
+			if movie.Year < minYear || movie.Year > maxYear {
+				errs = append(errs, fmt.Errorf("%s (%s): некорректный год %d", where, movie.ID, movie.Year))
+			}
+			if movie.Runtime < 0 {
+				errs = append(errs, fmt.Errorf("%s (%s): отрицательная продолжительность %d", where, movie.ID, movie.Runtime))
+			}
+			if movie.AgeRating != "" && !slices.Contains(models.AgeRatings, movie.AgeRating) {
+				errs = append(errs, fmt.Errorf("%s (%s): некорректное возрастное ограничение %q", where, movie.ID, movie.AgeRating))
+			}
+			seenTags := make(map[string]bool)
+			for _, tag := range movie.Tags {
+				if !tagPattern.MatchString(tag) {
+					errs = append(errs, fmt.Errorf("%s (%s): метка %q должна состоять из латинских букв в нижнем регистре, цифр и дефисов", where, movie.ID, tag))
+				} else if seenTags[tag] {
+					errs = append(errs, fmt.Errorf("%s (%s): метка %q указана дважды", where, movie.ID, tag))
+				}
+				seenTags[tag] = true
+			}
+			if movie.Category != category {
+				errs = append(errs, fmt.Errorf("%s (%s): категория %q не совпадает с группой", where, movie.ID, movie.Category))
+			}
+			ids, idErrs := externalIDs(movie)
+			for _, err := range idErrs {
+				errs = append(errs, fmt.Errorf("%s (%s): %w", where, movie.ID, err))
+			}
+			for provider, id := range ids {
+				key := externalKey(provider, id)
+				if other, ok := seenExternal[key]; ok {
+					errs = append(errs, fmt.Errorf("%s (%s): %s %s уже указан у %s", where, movie.ID, provider, id, other))
+				} else {
+					seenExternal[key] = movie.ID
+				}
+			}
+			for locale := range movie.Translations {
+				if !i18n.Supports(locale) || locale == i18n.Default {
+					errs = append(errs, fmt.Errorf("%s (%s): перевод на неподдерживаемый язык %q", where, movie.ID, locale))
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidatePeople проверяет список людей: непустые и уникальные идентификаторы и имена
+// и языки переводов имён
+func ValidatePeople(people []models.Person) error {
+	var errs []error
+	seen := make(map[string]int)
+	seenNames := make(map[string]string)
+
+	for i, person := range people {
+		where := fmt.Sprintf("people[%d]", i)
+		if person.ID == "" {
+			errs = append(errs, fmt.Errorf("%s: пустой id", where))
+		} else if other, ok := seen[person.ID]; ok {
+			errs = append(errs, fmt.Errorf("%s: id %q уже используется в people[%d]", where, person.ID, other))
+		} else {
+			seen[person.ID] = i
+		}
+		if person.Name == "" {
+			errs = append(errs, fmt.Errorf("%s (%s): пустое имя", where, person.ID))
+		} else if other, ok := seenNames[strings.ToLower(person.Name)]; ok {
+			errs = append(errs, fmt.Errorf("%s (%s): имя %q уже указано у %s", where, person.ID, person.Name, other))
+		} else {
+			seenNames[strings.ToLower(person.Name)] = person.ID
+		}
+		for locale := range person.Names {
+			if !i18n.Supports(locale) || locale == i18n.Default {
+				errs = append(errs, fmt.Errorf("%s (%s): имя на неподдерживаемом языке %q", where, person.ID, locale))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
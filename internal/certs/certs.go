@@ -0,0 +1,108 @@
+// Package certs настраивает HTTPS: готовые сертификаты из файлов или автоматический выпуск по ACME
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"movie-catalog/internal/config"
+)
+
+// Setup возвращает TLS-конфигурацию для HTTPS-сервера и обработчик для HTTP-адреса,
+// который отвечает на ACME http-01 проверки и перенаправляет остальные запросы на HTTPS.
+// Конфигурация включает HTTP/2.
+func Setup(cfg config.TLS) (*tls.Config, http.Handler, error) {
+	redirect := RedirectHandler(cfg.Addr)
+
+	if cfg.CertFile != "" {
+		if cfg.KeyFile == "" {
+			return nil, nil, errors.New("не указан файл ключа TLS_KEY_FILE")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("не удалось загрузить сертификат: %w", err)
+		}
+		return &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+			NextProtos:   []string{"h2", "http/1.1"},
+		}, redirect, nil
+	}
+
+	if len(cfg.ACMEDomains) == 0 {
+		return nil, nil, errors.New("не заданы ни сертификат, ни домены для ACME")
+	}
+
+	client := &acme.Client{DirectoryURL: cfg.ACMEDirectoryURL}
+	if cfg.ACMERootCA != "" {
+		httpClient, err := clientWithRootCA(cfg.ACMERootCA)
+		if err != nil {
+			return nil, nil, err
+		}
+		client.HTTPClient = httpClient
+	}
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(cfg.ACMECacheDir),
+		HostPolicy: autocert.HostWhitelist(cfg.ACMEDomains...),
+		Email:      cfg.ACMEEmail,
+		Client:     client,
+	}
+
+	tlsConfig := manager.TLSConfig()
+	tlsConfig.MinVersion = tls.VersionTLS12
+	return tlsConfig, manager.HTTPHandler(redirect), nil
+}
+
+// clientWithRootCA создаёт HTTP-клиент, доверяющий дополнительному корневому сертификату
+func clientWithRootCA(path string) (*http.Client, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать корневой сертификат ACME: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("в файле %s нет PEM-сертификатов", path)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return &http.Client{Transport: transport}, nil
+}
+
+// RedirectHandler перенаправляет запросы на тот же хост по HTTPS.
+// httpsAddr — адрес HTTPS-сервера; нестандартный порт добавляется к хосту.
+func RedirectHandler(httpsAddr string) http.Handler {
+	_, httpsPort, _ := net.SplitHostPort(httpsAddr)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Без порта IPv6-адрес остаётся в скобках: [::1]
+		host := strings.TrimSuffix(strings.TrimPrefix(r.Host, "["), "]")
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		switch {
+		case httpsPort != "" && httpsPort != "443":
+			host = net.JoinHostPort(host, httpsPort)
+		case strings.Contains(host, ":"):
+			host = "[" + host + "]"
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+}
@@ -0,0 +1,386 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"movie-catalog/internal/config"
+)
+
+// testCA — удостоверяющий центр, которым подписываются сертификаты в тестах
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, der: der}
+}
+
+// issue подписывает сертификат для ключа pub и доменов domains
+func (ca *testCA) issue(t *testing.T, pub any, domains []string) []byte {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: domains[0]},
+		DNSNames:     domains,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, pub, ca.key)
+	if err != nil {
+		t.Error(err)
+	}
+	return der
+}
+
+func writePEM(t *testing.T, path, kind string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetupCertFile(t *testing.T) {
+	ca := newTestCA(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writePEM(t, certFile, "CERTIFICATE", ca.issue(t, &key.PublicKey, []string{"films.test"}))
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+
+	tlsConfig, handler, err := Setup(config.TLS{Addr: ":8443", CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("сертификатов %d, ожидался 1", len(tlsConfig.Certificates))
+	}
+	if tlsConfig.MinVersion != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %x", tlsConfig.MinVersion)
+	}
+	if len(tlsConfig.NextProtos) == 0 || tlsConfig.NextProtos[0] != "h2" {
+		t.Errorf("NextProtos = %v, ожидался h2", tlsConfig.NextProtos)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://films.test/movies", nil))
+	if recorder.Code != http.StatusMovedPermanently {
+		t.Errorf("статус %d, ожидался редирект", recorder.Code)
+	}
+
+	if _, _, err := Setup(config.TLS{CertFile: certFile}); err == nil {
+		t.Error("Setup без файла ключа должен вернуть ошибку")
+	}
+	if _, _, err := Setup(config.TLS{}); err == nil {
+		t.Error("Setup без сертификата и доменов должен вернуть ошибку")
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		httpsAddr string
+		url       string
+		want      string
+	}{
+		{":443", "http://films.test/movies?page=2", "https://films.test/movies?page=2"},
+		{":443", "http://films.test:80/", "https://films.test/"},
+		{":8443", "http://films.test:8080/movie/1", "https://films.test:8443/movie/1"},
+		{"", "http://films.test/", "https://films.test/"},
+		{":8443", "http://[::1]/movies", "https://[::1]:8443/movies"},
+		{":8443", "http://[::1]:8080/", "https://[::1]:8443/"},
+		{":443", "http://[::1]/", "https://[::1]/"},
+		{":443", "http://[2001:db8::1]:80/", "https://[2001:db8::1]/"},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		RedirectHandler(tt.httpsAddr).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.url, nil))
+		if recorder.Code != http.StatusMovedPermanently {
+			t.Errorf("%s %s: статус %d", tt.httpsAddr, tt.url, recorder.Code)
+		}
+		if got := recorder.Header().Get("Location"); got != tt.want {
+			t.Errorf("%s %s: Location = %q, ожидался %q", tt.httpsAddr, tt.url, got, tt.want)
+		}
+	}
+}
+
+// acmeStub — минимальный ACME-сервер (RFC 8555) с одним заказом и проверкой http-01.
+// Проверку он проводит, вызывая обработчик challenge, который вернул Setup.
+type acmeStub struct {
+	t      *testing.T
+	ca     *testCA
+	server *httptest.Server
+	domain string
+	token  string
+
+	mu        sync.Mutex
+	challenge http.Handler
+	validated bool
+	issued    []byte
+}
+
+func newACMEStub(t *testing.T, domain string) *acmeStub {
+	stub := &acmeStub{t: t, ca: newTestCA(t), domain: domain, token: "test-token"}
+	stub.server = httptest.NewTLSServer(http.HandlerFunc(stub.serve))
+	t.Cleanup(stub.server.Close)
+	return stub
+}
+
+func (s *acmeStub) url(path string) string {
+	return s.server.URL + path
+}
+
+func (s *acmeStub) serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Replay-Nonce", base64.RawURLEncoding.EncodeToString(big.NewInt(time.Now().UnixNano()).Bytes()))
+	w.Header().Set("Cache-Control", "no-store")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/directory":
+		s.json(w, http.StatusOK, map[string]any{
+			"newNonce":   s.url("/nonce"),
+			"newAccount": s.url("/account"),
+			"newOrder":   s.url("/order"),
+			"revokeCert": s.url("/revoke"),
+			"keyChange":  s.url("/key-change"),
+		})
+	case "/nonce":
+		w.WriteHeader(http.StatusOK)
+	case "/account":
+		w.Header().Set("Location", s.url("/account/1"))
+		s.json(w, http.StatusCreated, map[string]any{"status": "valid"})
+	case "/order":
+		w.Header().Set("Location", s.url("/order/1"))
+		s.json(w, http.StatusCreated, s.order())
+	case "/order/1":
+		s.json(w, http.StatusOK, s.order())
+	case "/authz/1":
+		s.json(w, http.StatusOK, map[string]any{
+			"status":     s.status(),
+			"identifier": map[string]string{"type": "dns", "value": s.domain},
+			"challenges": []any{s.challengeJSON()},
+		})
+	case "/challenge/1":
+		s.validate()
+		s.json(w, http.StatusOK, s.challengeJSON())
+	case "/finalize/1":
+		if err := s.finalize(r); err != nil {
+			s.t.Error(err)
+			s.json(w, http.StatusBadRequest, map[string]any{
+				"type": "urn:ietf:params:acme:error:badCSR", "detail": err.Error(),
+			})
+			return
+		}
+		w.Header().Set("Location", s.url("/order/1"))
+		s.json(w, http.StatusOK, s.order())
+	case "/certificate/1":
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		w.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.issued}))
+		w.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.ca.der}))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *acmeStub) json(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (s *acmeStub) status() string {
+	if s.validated {
+		return "valid"
+	}
+	return "pending"
+}
+
+func (s *acmeStub) order() map[string]any {
+	order := map[string]any{
+		"status":         "pending",
+		"identifiers":    []any{map[string]string{"type": "dns", "value": s.domain}},
+		"authorizations": []string{s.url("/authz/1")},
+		"finalize":       s.url("/finalize/1"),
+	}
+	switch {
+	case s.issued != nil:
+		order["status"] = "valid"
+		order["certificate"] = s.url("/certificate/1")
+	case s.validated:
+		order["status"] = "ready"
+	}
+	return order
+}
+
+func (s *acmeStub) challengeJSON() map[string]any {
+	return map[string]any{
+		"type":   "http-01",
+		"url":    s.url("/challenge/1"),
+		"token":  s.token,
+		"status": s.status(),
+	}
+}
+
+// validate запрашивает у обработчика http-01 ответ на проверку, как это делает ACME-сервер
+func (s *acmeStub) validate() {
+	if s.challenge == nil {
+		s.t.Error("проверка запрошена до настройки обработчика http-01")
+		return
+	}
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "http://"+s.domain+"/.well-known/acme-challenge/"+s.token, nil)
+	s.challenge.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || !strings.HasPrefix(recorder.Body.String(), s.token+".") {
+		s.t.Errorf("ответ на проверку http-01: %d %q", recorder.Code, recorder.Body.String())
+		return
+	}
+	s.validated = true
+}
+
+// finalize выпускает сертификат по CSR из запроса
+func (s *acmeStub) finalize(r *http.Request) error {
+	var jws struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&jws); err != nil {
+		return err
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	if err != nil {
+		return err
+	}
+	var body struct {
+		CSR string `json:"csr"`
+	}
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return err
+	}
+	der, err := base64.RawURLEncoding.DecodeString(body.CSR)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return err
+	}
+	s.issued = s.ca.issue(s.t, csr.PublicKey, csr.DNSNames)
+	return nil
+}
+
+func TestSetupACME(t *testing.T) {
+	const domain = "films.test"
+	stub := newACMEStub(t, domain)
+
+	rootCA := filepath.Join(t.TempDir(), "root.pem")
+	writePEM(t, rootCA, "CERTIFICATE", stub.server.Certificate().Raw)
+
+	tlsConfig, handler, err := Setup(config.TLS{
+		Addr:             ":8443",
+		ACMEDomains:      []string{domain},
+		ACMEEmail:        "admin@films.test",
+		ACMEDirectoryURL: stub.url("/directory"),
+		ACMECacheDir:     t.TempDir(),
+		ACMERootCA:       rootCA,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub.mu.Lock()
+	stub.challenge = handler
+	stub.mu.Unlock()
+
+	// Запросы не к ACME-проверкам перенаправляются на HTTPS
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://films.test/movies", nil))
+	if got := recorder.Header().Get("Location"); recorder.Code != http.StatusMovedPermanently || got != "https://films.test:8443/movies" {
+		t.Errorf("редирект: %d %q", recorder.Code, got)
+	}
+
+	type result struct {
+		cert *tls.Certificate
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		cert, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{
+			ServerName:   domain,
+			CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+		})
+		done <- result{cert, err}
+	}()
+
+	var got result
+	select {
+	case got = <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("сертификат не выпущен за 30 секунд")
+	}
+	if got.err != nil {
+		t.Fatal(got.err)
+	}
+	leaf, err := x509.ParseCertificate(got.cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := leaf.VerifyHostname(domain); err != nil {
+		t.Error(err)
+	}
+	if leaf.Issuer.CommonName != "Test CA" {
+		t.Errorf("сертификат выпущен %q, ожидался Test CA", leaf.Issuer.CommonName)
+	}
+	if tlsConfig.MinVersion != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %x", tlsConfig.MinVersion)
+	}
+}
@@ -0,0 +1,162 @@
+// Package collections хранит подборки фильмов в JSON-файле и изменяет их через API
+package collections
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"movie-catalog/internal/jsonfile"
+	"movie-catalog/internal/models"
+)
+
+var (
+	// ErrNotFound — подборки с таким slug нет
+	ErrNotFound = errors.New("подборка не найдена")
+	// ErrExists — подборка с таким slug уже есть
+	ErrExists = errors.New("подборка с таким slug уже есть")
+	// ErrInvalid — подборка не прошла проверку Validate
+	ErrInvalid = errors.New("подборка не прошла проверку")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Store — потокобезопасное хранилище подборок в порядке их создания. Подборки
+// правят администраторы, поэтому файл небольшой и после каждого изменения
+// переписывается целиком.
+type Store struct {
+	collections *jsonfile.Value[[]models.Collection]
+}
+
+// Open загружает подборки из файла path и проверяет их; если файла нет,
+// подборок пока нет и файл появится при создании первой
+func Open(path string) (*Store, error) {
+	value, err := jsonfile.Open[[]models.Collection](path, "файл подборок")
+	if err != nil {
+		return nil, err
+	}
+	var invalid error
+	value.Read(func(list []models.Collection) {
+		for _, collection := range list {
+			if err := Validate(collection); err != nil {
+				invalid = fmt.Errorf("ошибка в файле подборок (%s): %w", collection.Slug, err)
+				return
+			}
+		}
+	})
+	if invalid != nil {
+		return nil, invalid
+	}
+	return &Store{collections: value}, nil
+}
+
+// Validate проверяет slug, название и отсутствие повторов в списке фильмов.
+// Ошибка оборачивает ErrInvalid.
+func Validate(collection models.Collection) error {
+	var errs []error
+	if !slugPattern.MatchString(collection.Slug) {
+		errs = append(errs, fmt.Errorf("slug %q должен состоять из латинских букв, цифр и дефисов", collection.Slug))
+	}
+	if collection.Title == "" {
+		errs = append(errs, errors.New("пустое название"))
+	}
+	seen := make(map[string]bool)
+	for _, id := range collection.MovieIDs {
+		if seen[id] {
+			errs = append(errs, fmt.Errorf("фильм %q указан дважды", id))
+		}
+		seen[id] = true
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalid, errors.Join(errs...))
+	}
+	return nil
+}
+
+// List возвращает все подборки в порядке создания
+func (s *Store) List() []models.Collection {
+	var list []models.Collection
+	s.collections.Read(func(collections []models.Collection) {
+		list = append([]models.Collection(nil), collections...)
+	})
+	return list
+}
+
+// Get ищет подборку по slug
+func (s *Store) Get(slug string) (models.Collection, bool) {
+	var (
+		found models.Collection
+		ok    bool
+	)
+	s.collections.Read(func(collections []models.Collection) {
+		if i := index(collections, slug); i >= 0 {
+			found, ok = collections[i], true
+		}
+	})
+	return found, ok
+}
+
+// Create добавляет подборку; даты создания и изменения проставляются автоматически
+func (s *Store) Create(collection models.Collection) (models.Collection, error) {
+	if err := Validate(collection); err != nil {
+		return models.Collection{}, err
+	}
+	err := s.collections.Update(func(collections []models.Collection) ([]models.Collection, error) {
+		if index(collections, collection.Slug) >= 0 {
+			return nil, ErrExists
+		}
+		now := time.Now().UTC()
+		collection.CreatedAt, collection.UpdatedAt = now, now
+		return append(append([]models.Collection{}, collections...), collection), nil
+	}, nil)
+	if err != nil {
+		return models.Collection{}, err
+	}
+	return collection, nil
+}
+
+// Update заменяет подборку slug; slug и дата создания не меняются
+func (s *Store) Update(slug string, collection models.Collection) (models.Collection, error) {
+	collection.Slug = slug
+	if err := Validate(collection); err != nil {
+		return models.Collection{}, err
+	}
+	err := s.collections.Update(func(collections []models.Collection) ([]models.Collection, error) {
+		i := index(collections, slug)
+		if i < 0 {
+			return nil, ErrNotFound
+		}
+		collection.CreatedAt = collections[i].CreatedAt
+		collection.UpdatedAt = time.Now().UTC()
+
+		updated := append([]models.Collection{}, collections...)
+		updated[i] = collection
+		return updated, nil
+	}, nil)
+	if err != nil {
+		return models.Collection{}, err
+	}
+	return collection, nil
+}
+
+// Delete удаляет подборку slug
+func (s *Store) Delete(slug string) error {
+	return s.collections.Update(func(collections []models.Collection) ([]models.Collection, error) {
+		i := index(collections, slug)
+		if i < 0 {
+			return nil, ErrNotFound
+		}
+		return append(append([]models.Collection{}, collections[:i]...), collections[i+1:]...), nil
+	}, nil)
+}
+
+// index возвращает позицию подборки slug в списке или -1
+func index(collections []models.Collection, slug string) int {
+	for i, collection := range collections {
+		if collection.Slug == slug {
+			return i
+		}
+	}
+	return -1
+}
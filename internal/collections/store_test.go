@@ -0,0 +1,102 @@
+package collections
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"movie-catalog/internal/models"
+)
+
+func openStore(t *testing.T) (*Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "collections.json")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, path
+}
+
+func TestCreateDuplicateSlug(t *testing.T) {
+	store, path := openStore(t)
+
+	nolan := models.Collection{Slug: "nolan", Title: "Фильмы Нолана", MovieIDs: []string{"inception"}}
+	if _, err := store.Create(nolan); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Create(models.Collection{Slug: "nolan", Title: "Другое"}); !errors.Is(err, ErrExists) {
+		t.Fatalf("повторный slug: ошибка %v, ожидалась ErrExists", err)
+	}
+
+	// Подборка сохранена в файл, а неудачная попытка ничего не изменила
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := reopened.List()
+	if len(list) != 1 || list[0].Title != nolan.Title {
+		t.Errorf("после перезапуска подборки %+v", list)
+	}
+}
+
+func TestUpdateKeepsCreatedAt(t *testing.T) {
+	store, _ := openStore(t)
+
+	created, err := store.Create(models.Collection{Slug: "space", Title: "Космос", MovieIDs: []string{"interstellar"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+
+	changed := models.Collection{
+		Slug:      "ignored",
+		Title:     "Космос и время",
+		MovieIDs:  []string{"interstellar", "arrival"},
+		CreatedAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	updated, err := store.Update("space", changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Slug != "space" {
+		t.Errorf("slug изменился на %q", updated.Slug)
+	}
+	if !updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("дата создания %v, ожидалась %v", updated.CreatedAt, created.CreatedAt)
+	}
+	if !updated.UpdatedAt.After(created.UpdatedAt) {
+		t.Errorf("дата изменения %v не позже %v", updated.UpdatedAt, created.UpdatedAt)
+	}
+	if got, _ := store.Get("space"); got.Title != changed.Title || len(got.MovieIDs) != 2 {
+		t.Errorf("в хранилище %+v", got)
+	}
+
+	if _, err := store.Update("missing", changed); !errors.Is(err, ErrNotFound) {
+		t.Errorf("изменение несуществующей: ошибка %v, ожидалась ErrNotFound", err)
+	}
+	if _, err := store.Update("space", models.Collection{Title: ""}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("пустое название: ошибка %v, ожидалась ErrInvalid", err)
+	}
+}
+
+func TestDeleteMissing(t *testing.T) {
+	store, _ := openStore(t)
+
+	if err := store.Delete("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("ошибка %v, ожидалась ErrNotFound", err)
+	}
+	if _, err := store.Create(models.Collection{Slug: "a", Title: "A"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("повторное удаление: ошибка %v, ожидалась ErrNotFound", err)
+	}
+	if list := store.List(); len(list) != 0 {
+		t.Errorf("осталось %+v", list)
+	}
+}
@@ -0,0 +1,242 @@
+// Package config читает настройки сервера из переменных окружения
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config содержит настройки сервера
+type Config struct {
+	// Addr — адрес HTTP-сервера. По умолчанию слушаются все интерфейсы;
+	// укажите "localhost:8080", если нужен только локальный доступ
+	Addr string
+	// AdminAddr — адрес служебного сервера с /metrics; не публикуйте его наружу
+	AdminAddr string
+	// LogLevel — уровень логирования: debug, info, warn, error
+	LogLevel string
+	// CatalogPath — путь к JSON-файлу каталога фильмов
+	CatalogPath string
+	// HistoryPath — путь к журналу правок фильмов (JSON Lines); файл только дописывается
+	HistoryPath string
+	// CollectionsPath — путь к JSON-файлу подборок; сервер сам изменяет его через API
+	CollectionsPath string
+	// RatingsPath — путь к JSON-файлу оценок и списков «посмотреть позже» зрителей
+	RatingsPath string
+	// RecommendInterval — как часто перестраивать модель рекомендаций, если оценки изменились
+	RecommendInterval time.Duration
+	// RecommendCPUShare — доля одного ядра процессора, которую может занимать
+	// перестройка модели рекомендаций, от 0 до 1
+	RecommendCPUShare float64
+	// NightsPath — путь к JSON-файлу голосований за фильм на вечер
+	NightsPath string
+	// AdminToken — токен для изменяющих запросов API (заголовок Authorization: Bearer).
+	// Если не задан, изменения через API отключены
+	AdminToken string
+	// CatalogReloadInterval — как часто проверять изменение файла каталога
+	CatalogReloadInterval time.Duration
+	// ShutdownDrainDelay — сколько ждать после перевода /readyz в «не готов»,
+	// чтобы обратный прокси успел снять сервер с балансировки
+	ShutdownDrainDelay time.Duration
+	// ShutdownTimeout — сколько ждать завершения активных запросов при остановке
+	ShutdownTimeout time.Duration
+	// TrustedProxies — адреса или подсети прокси, которым разрешено передавать
+	// адрес клиента в X-Forwarded-For. Пустой список — доверять только прямому соединению
+	TrustedProxies []string
+	// RateLimits — ограничения частоты запросов с одного IP по группам маршрутов
+	RateLimits RateLimits
+	// TLS — настройки HTTPS; если TLS не включён, сервер работает по HTTP на Addr
+	TLS TLS
+	// PublicURL — внешний адрес сайта вида https://films.example.com для абсолютных
+	// ссылок в лентах и карте сайта. Если не задан, адрес берётся из запроса
+	PublicURL string
+	// AllowedHosts — имена хостов, которые принимаются из заголовка Host для абсолютных
+	// ссылок, когда PublicURL не задан. По умолчанию только localhost
+	AllowedHosts []string
+	// CSPReportOnly — отправлять Content-Security-Policy-Report-Only вместо
+	// блокирующей политики, чтобы сначала собрать отчёты о нарушениях
+	CSPReportOnly bool
+}
+
+// TLS — настройки HTTPS. Сертификат берётся либо из файлов CertFile/KeyFile,
+// либо выпускается автоматически по ACME для доменов ACMEDomains.
+type TLS struct {
+	// Addr — адрес HTTPS-сервера. Addr основного конфига при этом
+	// обслуживает редирект на HTTPS и ACME http-01 проверки
+	Addr string
+	// CertFile и KeyFile — готовая пара сертификат/ключ в PEM
+	CertFile string
+	KeyFile  string
+	// ACMEDomains — домены для автоматического выпуска сертификатов
+	ACMEDomains []string
+	// ACMEEmail — контактный адрес для ACME-аккаунта
+	ACMEEmail string
+	// ACMEDirectoryURL — адрес каталога ACME-сервера (Let's Encrypt или локальный Pebble)
+	ACMEDirectoryURL string
+	// ACMECacheDir — каталог для хранения выпущенных сертификатов и ключа аккаунта
+	ACMECacheDir string
+	// ACMERootCA — PEM-файл корневого сертификата ACME-сервера, если он не из системного
+	// хранилища (например, у Pebble)
+	ACMERootCA string
+	// HSTSMaxAge — срок действия заголовка Strict-Transport-Security
+	HSTSMaxAge time.Duration
+	// HSTSIncludeSubdomains распространяет HSTS на поддомены. Включайте, только если
+	// все поддомены тоже работают по HTTPS
+	HSTSIncludeSubdomains bool
+}
+
+// Enabled сообщает, настроен ли HTTPS
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || len(t.ACMEDomains) > 0
+}
+
+// RateLimit — ограничение token bucket: средняя скорость (запросов в секунду)
+// и размер корзины (сколько запросов можно сделать подряд).
+// Нулевое значение отключает ограничение.
+type RateLimit struct {
+	RPS   float64
+	Burst int
+}
+
+// Enabled сообщает, включено ли ограничение
+func (l RateLimit) Enabled() bool {
+	return l.RPS > 0 && l.Burst > 0
+}
+
+// RateLimits — ограничения по группам маршрутов
+type RateLimits struct {
+	// Static — статические файлы: стили, скрипты, данные
+	Static RateLimit
+	// Pages — HTML-страницы
+	Pages RateLimit
+	// API — чтение через /api
+	API RateLimit
+	// Write — изменяющие запросы: правки, оценки, комментарии
+	Write RateLimit
+}
+
+// Load читает конфигурацию из окружения, подставляя значения по умолчанию
+func Load() Config {
+	return Config{
+		Addr:                  getEnv("ADDR", ":8080"),
+		AdminAddr:             getEnv("ADMIN_ADDR", "localhost:9090"),
+		LogLevel:              getEnv("LOG_LEVEL", "info"),
+		CatalogPath:           getEnv("CATALOG_PATH", "static/data/movies.json"),
+		HistoryPath:           getEnv("HISTORY_PATH", "data/history.jsonl"),
+		CollectionsPath:       getEnv("COLLECTIONS_PATH", "data/collections.json"),
+		AdminToken:            getEnv("ADMIN_TOKEN", ""),
+		RatingsPath:           getEnv("RATINGS_PATH", "data/ratings.json"),
+		RecommendInterval:     getDuration("RECOMMEND_INTERVAL", 10*time.Minute),
+		RecommendCPUShare:     getFraction("RECOMMEND_CPU_SHARE", 0.25),
+		NightsPath:            getEnv("NIGHTS_PATH", "data/nights.json"),
+		CatalogReloadInterval: getDuration("CATALOG_RELOAD_INTERVAL", 2*time.Second),
+		ShutdownDrainDelay:    getDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+		ShutdownTimeout:       getDuration("SHUTDOWN_TIMEOUT", 5*time.Second),
+		TrustedProxies:        getList("TRUSTED_PROXIES"),
+		RateLimits: RateLimits{
+			Static: getRateLimit("RATE_LIMIT_STATIC", RateLimit{RPS: 50, Burst: 200}),
+			Pages:  getRateLimit("RATE_LIMIT_PAGES", RateLimit{RPS: 5, Burst: 20}),
+			API:    getRateLimit("RATE_LIMIT_API", RateLimit{RPS: 10, Burst: 30}),
+			Write:  getRateLimit("RATE_LIMIT_WRITE", RateLimit{RPS: 0.2, Burst: 5}),
+		},
+		PublicURL:     strings.TrimRight(getEnv("PUBLIC_URL", ""), "/"),
+		AllowedHosts:  getListOr("ALLOWED_HOSTS", []string{"localhost", "127.0.0.1", "::1"}),
+		CSPReportOnly: getBool("CSP_REPORT_ONLY", false),
+		TLS: TLS{
+			Addr:                  getEnv("TLS_ADDR", ":8443"),
+			CertFile:              getEnv("TLS_CERT_FILE", ""),
+			KeyFile:               getEnv("TLS_KEY_FILE", ""),
+			ACMEDomains:           getList("ACME_DOMAINS"),
+			ACMEEmail:             getEnv("ACME_EMAIL", ""),
+			ACMEDirectoryURL:      getEnv("ACME_DIRECTORY_URL", "https://acme-v02.api.letsencrypt.org/directory"),
+			ACMECacheDir:          getEnv("ACME_CACHE_DIR", "certs"),
+			ACMERootCA:            getEnv("ACME_ROOT_CA", ""),
+			HSTSMaxAge:            getDuration("HSTS_MAX_AGE", 365*24*time.Hour),
+			HSTSIncludeSubdomains: getBool("HSTS_INCLUDE_SUBDOMAINS", false),
+		},
+	}
+}
+
+// getEnv возвращает значение переменной окружения или fallback, если она не задана
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getDuration разбирает длительность вида "5s" из окружения.
+// Некорректное или неположительное значение заменяется на fallback.
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// getList разбирает список значений через запятую
+func getList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getListOr разбирает список значений через запятую; пустой список заменяется на fallback
+func getListOr(key string, fallback []string) []string {
+	if values := getList(key); len(values) > 0 {
+		return values
+	}
+	return fallback
+}
+
+// getRateLimit разбирает ограничение вида "10:30" (запросов в секунду : размер корзины).
+// Значение "off" отключает ограничение, некорректное значение заменяется на fallback.
+func getRateLimit(key string, fallback RateLimit) RateLimit {
+	value := getEnv(key, "")
+	if value == "" {
+		return fallback
+	}
+	if value == "off" {
+		return RateLimit{}
+	}
+
+	rpsValue, burstValue, ok := strings.Cut(value, ":")
+	if !ok {
+		return fallback
+	}
+	rps, err := strconv.ParseFloat(strings.TrimSpace(rpsValue), 64)
+	if err != nil || rps <= 0 {
+		return fallback
+	}
+	burst, err := strconv.Atoi(strings.TrimSpace(burstValue))
+	if err != nil || burst <= 0 {
+		return fallback
+	}
+	return RateLimit{RPS: rps, Burst: burst}
+}
+
+// getFraction разбирает долю от 0 (не включая) до 1 из окружения.
+// Некорректное значение заменяется на fallback.
+func getFraction(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(getEnv(key, ""), 64)
+	if err != nil || value <= 0 || value > 1 {
+		return fallback
+	}
+	return value
+}
+
+// getBool разбирает логическое значение (true/false, 1/0) из окружения
+func getBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(getEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,97 @@
+package enrich
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache — источник-обёртка, сохраняющий ответы другого источника в каталоге на диске.
+// Запоминаются и найденные фильмы, и ErrNotFound, чтобы не тратить лимит запросов API
+// на повторные запуски.
+type Cache struct {
+	provider Provider
+	dir      string
+	ttl      time.Duration
+}
+
+// cacheEntry — запись кэша в файле
+type cacheEntry struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+	NotFound  bool      `json:"notFound,omitempty"`
+	Metadata  Metadata  `json:"metadata"`
+}
+
+// NewCache оборачивает provider кэшем в каталоге dir. Записи старше ttl
+// запрашиваются заново; ttl <= 0 — хранить без срока.
+func NewCache(provider Provider, dir string, ttl time.Duration) *Cache {
+	return &Cache{provider: provider, dir: dir, ttl: ttl}
+}
+
+// Name возвращает имя исходного источника
+func (c *Cache) Name() string {
+	return c.provider.Name()
+}
+
+// Fetch возвращает ответ из кэша или запрашивает исходный источник и сохраняет ответ
+func (c *Cache) Fetch(ctx context.Context, lookup Lookup) (Metadata, error) {
+	path := filepath.Join(c.dir, c.provider.Name(), cacheKey(lookup)+".json")
+
+	if data, err := os.ReadFile(path); err == nil {
+		var entry cacheEntry
+		if json.Unmarshal(data, &entry) == nil && (c.ttl <= 0 || time.Since(entry.FetchedAt) < c.ttl) {
+			if entry.NotFound {
+				return Metadata{}, ErrNotFound
+			}
+			return entry.Metadata, nil
+		}
+	}
+
+	meta, err := c.provider.Fetch(ctx, lookup)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		// Временные ошибки не кэшируются
+		return meta, err
+	}
+
+	entry := cacheEntry{FetchedAt: time.Now().UTC(), NotFound: err != nil, Metadata: meta}
+	if writeErr := writeCacheEntry(path, entry); writeErr != nil {
+		return meta, fmt.Errorf("не удалось сохранить кэш: %w", writeErr)
+	}
+	return meta, err
+}
+
+func writeCacheEntry(path string, entry cacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// cacheKey строит имя файла кэша по условиям поиска
+func cacheKey(lookup Lookup) string {
+	parts := []string{strings.ToLower(lookup.Title), strconv.Itoa(lookup.Year)}
+	providers := make([]string, 0, len(lookup.ExternalIDs))
+	for provider := range lookup.ExternalIDs {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	for _, provider := range providers {
+		parts = append(parts, provider+"="+lookup.ExternalIDs[provider])
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:12])
+}
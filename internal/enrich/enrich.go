@@ -0,0 +1,43 @@
+// Package enrich получает метаданные фильмов из внешних источников
+// (описание, постер, длительность, режиссёры, актёры, страны)
+package enrich
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound — источник не нашёл фильм
+var ErrNotFound = errors.New("фильм не найден")
+
+// Lookup — данные для поиска фильма. Если известен внешний идентификатор,
+// источник ищет по нему, иначе — по названию и году.
+type Lookup struct {
+	Title string
+	Year  int
+	// ExternalIDs — идентификаторы во внешних сервисах: kinopoisk, imdb, tmdb
+	ExternalIDs map[string]string
+}
+
+// Metadata — найденные данные о фильме. Пустые поля означают, что источник их не знает.
+type Metadata struct {
+	// Language — язык текстовых полей, например "en"
+	Language    string   `json:"language"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	PosterURL   string   `json:"posterUrl,omitempty"`
+	Runtime     int      `json:"runtime,omitempty"`
+	Directors   []string `json:"directors,omitempty"`
+	Cast        []string `json:"cast,omitempty"`
+	Countries   []string `json:"countries,omitempty"`
+	// ExternalIDs — идентификаторы фильма, которые сообщил источник
+	ExternalIDs map[string]string `json:"externalIds,omitempty"`
+}
+
+// Provider — источник метаданных
+type Provider interface {
+	// Name возвращает короткое имя источника для логов и ключей кэша
+	Name() string
+	// Fetch ищет фильм и возвращает его метаданные или ErrNotFound
+	Fetch(ctx context.Context, lookup Lookup) (Metadata, error)
+}
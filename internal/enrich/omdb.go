@@ -0,0 +1,131 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OMDbURL — адрес OMDb API по умолчанию
+const OMDbURL = "https://www.omdbapi.com/"
+
+// OMDb — источник метаданных на основе OMDb API (https://www.omdbapi.com).
+// Ищет по идентификатору IMDb, а без него — по названию и году. Тексты на английском.
+type OMDb struct {
+	// BaseURL — адрес API; в тестах подменяется адресом локальной заглушки
+	BaseURL string
+	APIKey  string
+	Client  *http.Client
+}
+
+// NewOMDb создаёт источник OMDb с ключом apiKey
+func NewOMDb(apiKey string) *OMDb {
+	return &OMDb{
+		BaseURL: OMDbURL,
+		APIKey:  apiKey,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name возвращает имя источника
+func (o *OMDb) Name() string {
+	return "omdb"
+}
+
+// omdbResponse — ответ OMDb; отсутствующие значения приходят строкой "N/A"
+type omdbResponse struct {
+	Response string `json:"Response"`
+	Error    string `json:"Error"`
+	Title    string `json:"Title"`
+	Runtime  string `json:"Runtime"`
+	Director string `json:"Director"`
+	Actors   string `json:"Actors"`
+	Country  string `json:"Country"`
+	Plot     string `json:"Plot"`
+	Poster   string `json:"Poster"`
+	IMDbID   string `json:"imdbID"`
+}
+
+// Fetch ищет фильм в OMDb
+func (o *OMDb) Fetch(ctx context.Context, lookup Lookup) (Metadata, error) {
+	query := url.Values{}
+	query.Set("apikey", o.APIKey)
+	query.Set("plot", "full")
+	query.Set("type", "movie")
+	if id := lookup.ExternalIDs["imdb"]; id != "" {
+		query.Set("i", id)
+	} else if lookup.Title != "" {
+		query.Set("t", lookup.Title)
+		if lookup.Year != 0 {
+			query.Set("y", strconv.Itoa(lookup.Year))
+		}
+	} else {
+		return Metadata{}, ErrNotFound
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.BaseURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return Metadata{}, err
+	}
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("omdb: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Metadata{}, fmt.Errorf("omdb: неожиданный ответ %s", resp.Status)
+	}
+
+	var body omdbResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Metadata{}, fmt.Errorf("omdb: некорректный ответ: %w", err)
+	}
+	if body.Response != "True" {
+		if strings.Contains(strings.ToLower(body.Error), "not found") {
+			return Metadata{}, ErrNotFound
+		}
+		return Metadata{}, fmt.Errorf("omdb: %s", body.Error)
+	}
+
+	meta := Metadata{
+		Language:    "en",
+		Title:       omdbValue(body.Title),
+		Description: omdbValue(body.Plot),
+		PosterURL:   omdbValue(body.Poster),
+		Directors:   omdbList(body.Director),
+		Cast:        omdbList(body.Actors),
+		Countries:   omdbList(body.Country),
+	}
+	if minutes, _, ok := strings.Cut(omdbValue(body.Runtime), " "); ok {
+		meta.Runtime, _ = strconv.Atoi(minutes)
+	}
+	if id := omdbValue(body.IMDbID); id != "" {
+		meta.ExternalIDs = map[string]string{"imdb": id}
+	}
+	return meta, nil
+}
+
+// omdbValue заменяет "N/A" пустой строкой
+func omdbValue(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "N/A" {
+		return ""
+	}
+	return value
+}
+
+// omdbList разбирает список через запятую: "Christopher Nolan, Jonathan Nolan"
+func omdbList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(omdbValue(value), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
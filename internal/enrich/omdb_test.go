@@ -0,0 +1,101 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+// omdbStub поднимает заглушку OMDb, которая знает один фильм
+func omdbStub(t *testing.T, requests *int32) *OMDb {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		query := r.URL.Query()
+		if query.Get("apikey") != "test-key" {
+			w.Write([]byte(`{"Response":"False","Error":"Invalid API key!"}`))
+			return
+		}
+		if query.Get("i") == "tt1375666" || (query.Get("t") == "Inception" && query.Get("y") == "2010") {
+			w.Write([]byte(`{
+				"Title": "Inception",
+				"Runtime": "148 min",
+				"Director": "Christopher Nolan",
+				"Actors": "Leonardo DiCaprio, Joseph Gordon-Levitt, Elliot Page",
+				"Country": "United States, United Kingdom",
+				"Plot": "A thief who steals corporate secrets.",
+				"Poster": "N/A",
+				"imdbID": "tt1375666",
+				"Response": "True"
+			}`))
+			return
+		}
+		w.Write([]byte(`{"Response":"False","Error":"Movie not found!"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider := NewOMDb("test-key")
+	provider.BaseURL = server.URL
+	provider.Client = server.Client()
+	return provider
+}
+
+func TestOMDbFetch(t *testing.T) {
+	var requests int32
+	provider := omdbStub(t, &requests)
+
+	want := Metadata{
+		Language:    "en",
+		Title:       "Inception",
+		Description: "A thief who steals corporate secrets.",
+		Runtime:     148,
+		Directors:   []string{"Christopher Nolan"},
+		Cast:        []string{"Leonardo DiCaprio", "Joseph Gordon-Levitt", "Elliot Page"},
+		Countries:   []string{"United States", "United Kingdom"},
+		ExternalIDs: map[string]string{"imdb": "tt1375666"},
+	}
+	for _, lookup := range []Lookup{
+		{ExternalIDs: map[string]string{"imdb": "tt1375666"}},
+		{Title: "Inception", Year: 2010},
+	} {
+		got, err := provider.Fetch(context.Background(), lookup)
+		if err != nil {
+			t.Fatalf("Fetch(%+v): %v", lookup, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Fetch(%+v) = %+v, want %+v", lookup, got, want)
+		}
+	}
+
+	if _, err := provider.Fetch(context.Background(), Lookup{Title: "Нет такого", Year: 1900}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Fetch неизвестного фильма: ошибка %v, want ErrNotFound", err)
+	}
+
+	provider.APIKey = "wrong"
+	if _, err := provider.Fetch(context.Background(), Lookup{Title: "Inception"}); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Fetch с неверным ключом: ошибка %v, want ошибку API", err)
+	}
+}
+
+func TestCacheStoresResponses(t *testing.T) {
+	var requests int32
+	cache := NewCache(omdbStub(t, &requests), t.TempDir(), 0)
+
+	found := Lookup{Title: "Inception", Year: 2010}
+	missing := Lookup{Title: "Нет такого", Year: 1900}
+	for i := 0; i < 2; i++ {
+		if meta, err := cache.Fetch(context.Background(), found); err != nil || meta.Runtime != 148 {
+			t.Fatalf("Fetch(%+v) = %+v, %v", found, meta, err)
+		}
+		if _, err := cache.Fetch(context.Background(), missing); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Fetch(%+v): ошибка %v, want ErrNotFound", missing, err)
+		}
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("запросов к API: %d, want 2 — повторные ответы должны браться из кэша", got)
+	}
+}
@@ -0,0 +1,106 @@
+// Package events рассылает события об изменениях каталога и о ходе голосований
+// подписчикам (например, SSE-клиентам)
+package events
+
+import "sync"
+
+// Типы событий каталога
+const (
+	MovieAdded      = "movie.added"
+	MovieUpdated    = "movie.updated"
+	MovieRemoved    = "movie.removed"
+	CatalogReloaded = "catalog.reloaded"
+)
+
+// Типы событий голосования за фильм на вечер
+const (
+	NightUpdated = "night.updated"
+	NightClosed  = "night.closed"
+)
+
+// Размер буфера событий подписчика. Если клиент не успевает читать
+// и буфер заполнен, новые события для него отбрасываются.
+const subscriberBuffer = 64
+
+// Event — событие с порядковым номером, типом и данными для сериализации в JSON
+type Event struct {
+	ID   uint64
+	Type string
+	Data interface{}
+}
+
+// Broker рассылает опубликованные события всем текущим подписчикам
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[chan Event]struct{}
+	lastID uint64
+	closed bool
+}
+
+// NewBroker создаёт пустой брокер
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[chan Event]struct{})}
+}
+
+// Subscribe возвращает канал событий и функцию отписки.
+// Канал закрывается при отписке или закрытии брокера.
+func (b *Broker) Subscribe() (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, subscriberBuffer)
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subs[ch] = struct{}{}
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Publish отправляет событие всем подписчикам, не блокируясь на медленных
+func (b *Broker) Publish(eventType string, data interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	b.lastID++
+	event := Event{ID: b.lastID, Type: eventType, Data: data}
+	for ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribers возвращает количество текущих подписчиков
+func (b *Broker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// Close закрывает каналы всех подписчиков, чтобы долгие соединения
+// завершились до остановки сервера. Последующие публикации игнорируются.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for ch := range b.subs {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
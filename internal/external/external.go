@@ -0,0 +1,114 @@
+// Package external разбирает и формирует ссылки на внешние сервисы о кино:
+// Кинопоиск, IMDb и TMDB
+package external
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Provider — внешний сервис с идентификаторами фильмов
+type Provider string
+
+// Поддерживаемые сервисы
+const (
+	Kinopoisk Provider = "kinopoisk"
+	IMDb      Provider = "imdb"
+	TMDB      Provider = "tmdb"
+)
+
+// Providers возвращает все поддерживаемые сервисы в порядке отображения
+func Providers() []Provider {
+	return []Provider{Kinopoisk, IMDb, TMDB}
+}
+
+// ErrUnknownURL — ссылка не относится ни к одному поддерживаемому сервису
+var ErrUnknownURL = errors.New("ссылка не относится к Кинопоиску, IMDb или TMDB")
+
+// Форматы идентификаторов
+var idPatterns = map[Provider]*regexp.Regexp{
+	Kinopoisk: regexp.MustCompile(`^[1-9][0-9]*$`),
+	IMDb:      regexp.MustCompile(`^tt[0-9]{7,}$`),
+	TMDB:      regexp.MustCompile(`^[1-9][0-9]*$`),
+}
+
+// Known сообщает, поддерживается ли сервис
+func (p Provider) Known() bool {
+	_, ok := idPatterns[p]
+	return ok
+}
+
+// Name возвращает отображаемое название сервиса
+func (p Provider) Name() string {
+	switch p {
+	case Kinopoisk:
+		return "Кинопоиск"
+	case IMDb:
+		return "IMDb"
+	case TMDB:
+		return "TMDB"
+	default:
+		return string(p)
+	}
+}
+
+// Validate проверяет формат идентификатора для сервиса
+func Validate(p Provider, id string) error {
+	pattern, ok := idPatterns[p]
+	if !ok {
+		return fmt.Errorf("неизвестный сервис %q", p)
+	}
+	if !pattern.MatchString(id) {
+		return fmt.Errorf("некорректный идентификатор %s: %q", p.Name(), id)
+	}
+	return nil
+}
+
+// URL возвращает ссылку на страницу фильма в сервисе
+func URL(p Provider, id string) string {
+	switch p {
+	case Kinopoisk:
+		return "https://www.kinopoisk.ru/film/" + id + "/"
+	case IMDb:
+		return "https://www.imdb.com/title/" + id + "/"
+	case TMDB:
+		return "https://www.themoviedb.org/movie/" + id
+	default:
+		return ""
+	}
+}
+
+// ParseURL извлекает сервис и идентификатор из ссылки вида
+// https://www.kinopoisk.ru/film/1044002/, https://www.imdb.com/title/tt1375666/
+// или https://www.themoviedb.org/movie/27205-inception
+func ParseURL(raw string) (Provider, string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", "", err
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	var provider Provider
+	var id string
+	switch {
+	case host == "kinopoisk.ru" && len(segments) >= 2 && (segments[0] == "film" || segments[0] == "series"):
+		provider, id = Kinopoisk, segments[1]
+	case (host == "imdb.com" || host == "m.imdb.com") && len(segments) >= 2 && segments[0] == "title":
+		provider, id = IMDb, segments[1]
+	case host == "themoviedb.org" && len(segments) >= 2 && segments[0] == "movie":
+		// В адресе TMDB после идентификатора может идти название: 27205-inception
+		provider = TMDB
+		id, _, _ = strings.Cut(segments[1], "-")
+	default:
+		return "", "", ErrUnknownURL
+	}
+
+	if err := Validate(provider, id); err != nil {
+		return "", "", err
+	}
+	return provider, id, nil
+}
@@ -0,0 +1,171 @@
+// Package feed формирует ленты RSS 2.0 и Atom 1.0
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Feed — лента, не зависящая от формата
+type Feed struct {
+	Title       string
+	Description string
+	// Link — адрес страницы сайта, которой соответствует лента
+	Link string
+	// Self — адрес самой ленты
+	Self     string
+	Language string
+	Updated  time.Time
+	Items    []Item
+}
+
+// Item — запись ленты
+type Item struct {
+	ID        string
+	Title     string
+	Link      string
+	Summary   string
+	Published time.Time
+	// Enclosure — вложение записи (постер), необязательно
+	Enclosure *Enclosure
+}
+
+// Enclosure — файл, приложенный к записи
+type Enclosure struct {
+	URL  string
+	Type string
+}
+
+// RSS возвращает ленту в формате RSS 2.0
+func (f Feed) RSS() ([]byte, error) {
+	channel := rssChannel{
+		Title:       f.Title,
+		Link:        f.Link,
+		Description: f.Description,
+		Language:    f.Language,
+		AtomLink:    &atomLink{Href: f.Self, Rel: "self", Type: "application/rss+xml"},
+	}
+	if !f.Updated.IsZero() {
+		channel.LastBuildDate = f.Updated.Format(time.RFC1123Z)
+	}
+	for _, item := range f.Items {
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Summary,
+			GUID:        rssGUID{Value: item.ID, IsPermaLink: item.ID == item.Link},
+			PubDate:     item.Published.Format(time.RFC1123Z),
+		}
+		if item.Enclosure != nil {
+			// Размер постера заранее неизвестен; спецификация допускает 0
+			entry.Enclosure = &rssEnclosure{URL: item.Enclosure.URL, Type: item.Enclosure.Type, Length: 0}
+		}
+		channel.Items = append(channel.Items, entry)
+	}
+
+	return marshal(rss{Version: "2.0", AtomNS: atomNS, Channel: channel})
+}
+
+// Atom возвращает ленту в формате Atom 1.0
+func (f Feed) Atom() ([]byte, error) {
+	doc := atomFeed{
+		Title:    f.Title,
+		ID:       f.Self,
+		Subtitle: f.Description,
+		Lang:     f.Language,
+		Updated:  f.Updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.Self, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+		},
+	}
+	for _, item := range f.Items {
+		entry := atomEntry{
+			Title:     item.Title,
+			ID:        item.ID,
+			Published: item.Published.Format(time.RFC3339),
+			Updated:   item.Published.Format(time.RFC3339),
+			Summary:   item.Summary,
+			Links:     []atomLink{{Href: item.Link, Rel: "alternate", Type: "text/html"}},
+		}
+		if item.Enclosure != nil {
+			entry.Links = append(entry.Links, atomLink{Href: item.Enclosure.URL, Rel: "enclosure", Type: item.Enclosure.Type})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return marshal(doc)
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+const atomNS = "http://www.w3.org/2005/Atom"
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      *atomLink `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Description string        `xml:"description"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int    `xml:"length,attr"`
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang     string      `xml:"xml:lang,attr,omitempty"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title     string     `xml:"title"`
+	ID        string     `xml:"id"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Summary   string     `xml:"summary,omitempty"`
+	Links     []atomLink `xml:"link"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"math"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// Complexity — размер GraphQL-запроса до его выполнения
+type Complexity struct {
+	// Depth — наибольшая вложенность полей; поля верхнего уровня имеют глубину 1
+	Depth int
+	// Fields — количество запрошенных полей с учётом раскрытых фрагментов
+	Fields int
+}
+
+// Analyze разбирает запрос и считает его сложность по всем операциям.
+// Поля интроспекции (__schema, __type, __typename) не учитываются: схема
+// интроспекции конечна и нужна GraphiQL. Ошибку разбора возвращает как есть.
+func Analyze(query string) (Complexity, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return Complexity{}, err
+	}
+
+	w := &complexityWalker{
+		fragments: make(map[string]*ast.SelectionSet),
+		measured:  make(map[string]Complexity),
+		visiting:  make(map[string]bool),
+	}
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok && fragment.Name != nil {
+			w.fragments[fragment.Name.Value] = fragment.SelectionSet
+		}
+	}
+
+	var total Complexity
+	for _, def := range doc.Definitions {
+		if operation, ok := def.(*ast.OperationDefinition); ok {
+			c := w.selections(operation.SelectionSet)
+			total.Depth = max(total.Depth, c.Depth)
+			total.Fields = saturatingAdd(total.Fields, c.Fields)
+		}
+	}
+	return total, nil
+}
+
+// complexityWalker обходит запрос; сложность каждого фрагмента считается один раз,
+// поэтому вложенные фрагменты не раскрываются экспоненциально
+type complexityWalker struct {
+	fragments map[string]*ast.SelectionSet
+	measured  map[string]Complexity
+	visiting  map[string]bool
+}
+
+func (w *complexityWalker) selections(set *ast.SelectionSet) Complexity {
+	var c Complexity
+	if set == nil {
+		return c
+	}
+	for _, selection := range set.Selections {
+		var inner Complexity
+		switch s := selection.(type) {
+		case *ast.Field:
+			if s.Name != nil && strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			inner = w.selections(s.SelectionSet)
+			inner.Depth++
+			inner.Fields = saturatingAdd(inner.Fields, 1)
+		case *ast.InlineFragment:
+			inner = w.selections(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Name != nil {
+				inner = w.fragment(s.Name.Value)
+			}
+		}
+		c.Depth = max(c.Depth, inner.Depth)
+		c.Fields = saturatingAdd(c.Fields, inner.Fields)
+	}
+	return c
+}
+
+// fragment считает сложность именованного фрагмента. Циклические ссылки
+// не учитываются: такой запрос всё равно отклонит проверка схемы.
+func (w *complexityWalker) fragment(name string) Complexity {
+	if c, ok := w.measured[name]; ok {
+		return c
+	}
+	if w.visiting[name] {
+		return Complexity{}
+	}
+	w.visiting[name] = true
+	c := w.selections(w.fragments[name])
+	delete(w.visiting, name)
+	w.measured[name] = c
+	return c
+}
+
+func saturatingAdd(a, b int) int {
+	if a > math.MaxInt32-b {
+		return math.MaxInt32
+	}
+	return a + b
+}
@@ -0,0 +1,305 @@
+// Package graph описывает GraphQL-схему каталога фильмов
+package graph
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+
+	"movie-catalog/internal/catalog"
+	"movie-catalog/internal/external"
+	"movie-catalog/internal/models"
+)
+
+// Source — данные каталога, доступные через GraphQL
+type Source interface {
+	Find(q catalog.Query) []models.Movie
+	Movie(id string) (models.Movie, bool)
+	ByExternalID(provider, id string) (models.Movie, bool)
+	Category(key string) ([]models.Movie, bool)
+	Categories() []string
+	Sizes() map[string]int
+}
+
+// Ratings — оценки фильмов зрителями
+type Ratings interface {
+	Rating(userID, movieID string) (int, bool)
+}
+
+type viewerKey struct{}
+
+// WithViewer сохраняет в контексте идентификатор зрителя, чьи оценки отдаёт поле Movie.rating
+func WithViewer(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, viewerKey{}, userID)
+}
+
+// viewer возвращает идентификатор зрителя из контекста или пустую строку
+func viewer(ctx context.Context) string {
+	userID, _ := ctx.Value(viewerKey{}).(string)
+	return userID
+}
+
+// Максимальное количество фильмов в одном списке
+const maxLimit = 100
+
+// NewSchema строит схему с запросами movie, movieByExternalId, movies, search, category и categories.
+// Поле Movie.rating берёт оценку зрителя из контекста (см. WithViewer) в ratings.
+func NewSchema(src Source, ratings Ratings) (graphql.Schema, error) {
+	categoryType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Category",
+		Description: "Категория фильмов",
+		Fields: graphql.Fields{
+			"key":   &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "Ключ категории, например drama"},
+			"name":  &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "Отображаемое название"},
+			"count": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "Количество фильмов"},
+		},
+	})
+
+	externalIDType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ExternalID",
+		Description: "Идентификатор фильма во внешнем сервисе",
+		Fields: graphql.Fields{
+			"provider": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "kinopoisk, imdb или tmdb"},
+			"id":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"url":      &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "Ссылка на страницу фильма в сервисе"},
+		},
+	})
+
+	// optional отдаёт null вместо пустого значения необязательного поля
+	optional := func(t graphql.Output, get func(models.Movie) interface{}) *graphql.Field {
+		return &graphql.Field{Type: t, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			switch value := get(p.Source.(models.Movie)); value {
+			case "", 0:
+				return nil, nil
+			default:
+				return value, nil
+			}
+		}}
+	}
+	// names отдаёт пустой список вместо отсутствующего
+	names := func(description string, get func(models.Movie) []string) *graphql.Field {
+		return &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: description,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if list := get(p.Source.(models.Movie)); list != nil {
+					return list, nil
+				}
+				return []string{}, nil
+			},
+		}
+	}
+
+	movieType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Movie",
+		Description: "Фильм каталога",
+		Fields: graphql.Fields{
+			"id":              &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"title":           &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"year":            &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"description":     &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "Краткое описание"},
+			"fullDescription": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "Полное описание"},
+			"imagePath":       &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "Адрес постера"},
+			"link":            &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "Ссылка на Кинопоиск"},
+			"createdAt":       &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Description: "Когда фильм добавлен в каталог"},
+			"originalTitle":   optional(graphql.String, func(m models.Movie) interface{} { return m.OriginalTitle }),
+			"runtime":         optional(graphql.Int, func(m models.Movie) interface{} { return m.Runtime }),
+			"ageRating":       optional(graphql.String, func(m models.Movie) interface{} { return m.AgeRating }),
+			"tagline":         optional(graphql.String, func(m models.Movie) interface{} { return m.Tagline }),
+			"directors":       names("Режиссёры", func(m models.Movie) []string { return m.Directors }),
+			"cast":            names("Актёры главных ролей", func(m models.Movie) []string { return m.Cast }),
+			"countries":       names("Страны производства", func(m models.Movie) []string { return m.Countries }),
+			"tags":            names("Метки тем, например time-travel", func(m models.Movie) []string { return m.Tags }),
+			"externalIds": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(externalIDType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					movie := p.Source.(models.Movie)
+					ids := []map[string]string{}
+					for _, provider := range external.Providers() {
+						if id, ok := movie.ExternalIDs[string(provider)]; ok {
+							ids = append(ids, map[string]string{"provider": string(provider), "id": id, "url": external.URL(provider, id)})
+						}
+					}
+					return ids, nil
+				},
+			},
+			"rating": &graphql.Field{
+				Type:        graphql.Int,
+				Description: "Оценка фильма текущим зрителем; null, если он фильм не оценивал",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					userID := viewer(p.Context)
+					if userID == "" {
+						return nil, nil
+					}
+					if rating, ok := ratings.Rating(userID, p.Source.(models.Movie).ID); ok {
+						return rating, nil
+					}
+					return nil, nil
+				},
+			},
+			"category": &graphql.Field{
+				Type: graphql.NewNonNull(categoryType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					movie := p.Source.(models.Movie)
+					return category(src, movie.Category), nil
+				},
+			},
+		},
+	})
+
+	// Фильмы категории добавляются после объявления movieType, чтобы разорвать цикл типов
+	categoryType.AddFieldConfig("movies", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(movieType))),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			movies, _ := src.Category(p.Source.(models.Category).Key)
+			return movies, nil
+		},
+	})
+
+	pageArgs := graphql.FieldConfigArgument{
+		"limit":  &graphql.ArgumentConfig{Type: graphql.Int, Description: "Сколько фильмов вернуть (не больше 100)"},
+		"offset": &graphql.ArgumentConfig{Type: graphql.Int, Description: "Сколько фильмов пропустить"},
+	}
+	withPage := func(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+		for name, arg := range pageArgs {
+			args[name] = arg
+		}
+		return args
+	}
+	movieList := graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(movieType)))
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"movie": &graphql.Field{
+				Type:        movieType,
+				Description: "Фильм по идентификатору",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					movie, ok := src.Movie(p.Args["id"].(string))
+					if !ok {
+						return nil, nil
+					}
+					return movie, nil
+				},
+			},
+			"movieByExternalId": &graphql.Field{
+				Type:        movieType,
+				Description: "Фильм по идентификатору во внешнем сервисе",
+				Args: graphql.FieldConfigArgument{
+					"provider": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String), Description: "kinopoisk, imdb или tmdb"},
+					"id":       &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					movie, ok := src.ByExternalID(p.Args["provider"].(string), p.Args["id"].(string))
+					if !ok {
+						return nil, nil
+					}
+					return movie, nil
+				},
+			},
+			"movies": &graphql.Field{
+				Type:        movieList,
+				Description: "Фильмы с фильтрами по категории, годам, людям, странам, продолжительности, возрасту и меткам",
+				Args: withPage(graphql.FieldConfigArgument{
+					"category":    &graphql.ArgumentConfig{Type: graphql.String},
+					"yearFrom":    &graphql.ArgumentConfig{Type: graphql.Int},
+					"yearTo":      &graphql.ArgumentConfig{Type: graphql.Int},
+					"director":    &graphql.ArgumentConfig{Type: graphql.String, Description: "Часть имени режиссёра"},
+					"actor":       &graphql.ArgumentConfig{Type: graphql.String, Description: "Часть имени актёра"},
+					"country":     &graphql.ArgumentConfig{Type: graphql.String},
+					"runtimeFrom": &graphql.ArgumentConfig{Type: graphql.Int, Description: "Минут, не меньше"},
+					"runtimeTo":   &graphql.ArgumentConfig{Type: graphql.Int, Description: "Минут, не больше"},
+					"maxAge":      &graphql.ArgumentConfig{Type: graphql.Int, Description: "Возраст зрителя: ограничение не старше maxAge+"},
+					"tags":        &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Метки фильма"},
+					"anyTag":      &graphql.ArgumentConfig{Type: graphql.Boolean, Description: "true — достаточно одной метки из tags, иначе нужны все"},
+				}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					q := catalog.Query{}
+					q.Category, _ = p.Args["category"].(string)
+					q.YearFrom, _ = p.Args["yearFrom"].(int)
+					q.YearTo, _ = p.Args["yearTo"].(int)
+					q.Director, _ = p.Args["director"].(string)
+					q.Actor, _ = p.Args["actor"].(string)
+					q.Country, _ = p.Args["country"].(string)
+					q.RuntimeFrom, _ = p.Args["runtimeFrom"].(int)
+					q.RuntimeTo, _ = p.Args["runtimeTo"].(int)
+					if age, ok := p.Args["maxAge"].(int); ok {
+						q.MaxAge = &age
+					}
+					tags, _ := p.Args["tags"].([]interface{})
+					for _, tag := range tags {
+						q.Tags = append(q.Tags, tag.(string))
+					}
+					q.AnyTag, _ = p.Args["anyTag"].(bool)
+					return page(src.Find(q), p.Args), nil
+				},
+			},
+			"search": &graphql.Field{
+				Type:        movieList,
+				Description: "Поиск по названиям, описаниям и именам; совпадения в названии идут первыми",
+				Args: withPage(graphql.FieldConfigArgument{
+					"query": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					text := p.Args["query"].(string)
+					if text == "" {
+						return []models.Movie{}, nil
+					}
+					return page(src.Find(catalog.Query{Text: text}), p.Args), nil
+				},
+			},
+			"category": &graphql.Field{
+				Type:        categoryType,
+				Description: "Категория по ключу",
+				Args: graphql.FieldConfigArgument{
+					"key": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					key := p.Args["key"].(string)
+					if _, ok := src.Category(key); !ok {
+						return nil, nil
+					}
+					return category(src, key), nil
+				},
+			},
+			"categories": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType))),
+				Description: "Все категории",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					categories := []models.Category{}
+					for _, key := range src.Categories() {
+						categories = append(categories, category(src, key))
+					}
+					return categories, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+func category(src Source, key string) models.Category {
+	return models.Category{Key: key, Name: models.CategoryName(key), Count: src.Sizes()[key]}
+}
+
+// page применяет аргументы limit и offset к списку
+func page(movies []models.Movie, args map[string]interface{}) []models.Movie {
+	offset, _ := args["offset"].(int)
+	limit, ok := args["limit"].(int)
+	if !ok || limit <= 0 || limit > maxLimit {
+		limit = maxLimit
+	}
+
+	if offset < 0 || offset >= len(movies) {
+		return []models.Movie{}
+	}
+	movies = movies[offset:]
+	if len(movies) > limit {
+		movies = movies[:limit]
+	}
+	return movies
+}
@@ -0,0 +1,64 @@
+// Package health отслеживает готовность сервера принимать трафик
+package health
+
+import "sync/atomic"
+
+// CatalogState — сведения о каталоге, нужные для проверки готовности
+type CatalogState interface {
+	Loaded() bool
+	Version() string
+	Count() int
+}
+
+// Status — ответ проверки готовности
+type Status struct {
+	Ready          bool            `json:"ready"`
+	Checks         map[string]bool `json:"checks"`
+	CatalogVersion string          `json:"catalogVersion,omitempty"`
+	Movies         int             `json:"movies"`
+}
+
+// Probe собирает признаки готовности: шаблоны разобраны, каталог загружен
+// и проверен, сервер не находится в процессе остановки
+type Probe struct {
+	catalog        CatalogState
+	templatesReady atomic.Bool
+	shuttingDown   atomic.Bool
+}
+
+// NewProbe создаёт проверку готовности для каталога
+func NewProbe(catalog CatalogState) *Probe {
+	return &Probe{catalog: catalog}
+}
+
+// SetTemplatesReady отмечает, что шаблоны успешно разобраны
+func (p *Probe) SetTemplatesReady() {
+	p.templatesReady.Store(true)
+}
+
+// SetShuttingDown переводит сервер в состояние остановки:
+// с этого момента проверка готовности не проходит
+func (p *Probe) SetShuttingDown() {
+	p.shuttingDown.Store(true)
+}
+
+// Status возвращает текущее состояние готовности
+func (p *Probe) Status() Status {
+	checks := map[string]bool{
+		"templates": p.templatesReady.Load(),
+		"catalog":   p.catalog.Loaded(),
+		"running":   !p.shuttingDown.Load(),
+	}
+
+	ready := true
+	for _, ok := range checks {
+		ready = ready && ok
+	}
+
+	return Status{
+		Ready:          ready,
+		Checks:         checks,
+		CatalogVersion: p.catalog.Version(),
+		Movies:         p.catalog.Count(),
+	}
+}
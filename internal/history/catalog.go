@@ -0,0 +1,203 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"movie-catalog/internal/catalog"
+	"movie-catalog/internal/models"
+)
+
+// Sync записывает в журнал изменения файла каталога catalogPath, которых в журнале
+// ещё нет, например правки вручную. При первом вызове все фильмы попадают в журнал
+// как созданные.
+func (l *Log) Sync(catalogPath string) ([]models.MovieRevision, error) {
+	unlock, err := l.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	_, _, synced, err := l.syncLocked(catalogPath)
+	return synced, err
+}
+
+// Draft — каталог, прочитанный утилитой для правки
+type Draft struct {
+	// Movies — фильмы по категориям; утилита изменяет их на месте
+	Movies map[string][]models.Movie
+
+	path string
+	base map[string]fields
+}
+
+// ReadCatalog читает файл каталога для правки и запоминает прочитанное состояние,
+// чтобы Save перенёс в файл только изменения утилиты
+func ReadCatalog(path string) (*Draft, error) {
+	movies, err := catalog.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Draft{Movies: movies, path: path, base: catalogFields(movies)}, nil
+}
+
+// Save записывает в файл каталога изменения, которые утилита внесла в draft.
+// Правки, сделанные в файле после чтения, сохраняются: в текущий файл переносятся
+// только поля, которые изменила утилита, а сами правки файла попадают в журнал
+// раньше её правок. Прежний файл остаётся рядом с расширением .bak.
+// Каталог, не прошедший catalog.Validate, не записывается: ошибка оборачивает ErrInvalid.
+// Возвращает записанные в журнал правки утилиты.
+func (l *Log) Save(draft *Draft, author Author) ([]models.MovieRevision, error) {
+	unlock, err := l.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	list, current, _, err := l.syncLocked(draft.path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := copyCatalog(current)
+	currentFields := catalogFields(current)
+	for _, revision := range revisions(draft.base, catalogFields(draft.Movies), author, time.Time{}, 0) {
+		id := revision.MovieID
+		if revision.Action == models.RevisionDelete {
+			place(result, id, nil)
+			continue
+		}
+		movie, ok := currentFields[id]
+		if !ok && revision.Action != models.RevisionCreate {
+			// Фильм удалили из файла, пока утилита с ним работала
+			continue
+		}
+		if err := place(result, id, movie.apply(revision.Changes)); err != nil {
+			return nil, err
+		}
+	}
+	if err := catalog.Validate(result); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	return l.writeLocked(draft.path, list, current, result, author, 0)
+}
+
+// Revert возвращает фильм movieID к состоянию после правки revision: изменяет файл
+// каталога catalogPath и записывает возврат в журнал отдельной правкой. Если фильм
+// после этой правки был удалён, он удаляется из каталога. Пустой список означает,
+// что фильм уже в этом состоянии.
+func (l *Log) Revert(catalogPath, movieID string, revision int, author Author) ([]models.MovieRevision, error) {
+	unlock, err := l.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	list, current, _, err := l.syncLocked(catalogPath)
+	if err != nil {
+		return nil, err
+	}
+	found := -1
+	for i, r := range list {
+		if r.Revision == revision && r.MovieID == movieID {
+			found = i
+			break
+		}
+	}
+	if found < 0 {
+		return nil, ErrNotFound
+	}
+
+	result := copyCatalog(current)
+	if err := place(result, movieID, replay(list[:found+1])[movieID]); err != nil {
+		return nil, err
+	}
+	if err := catalog.Validate(result); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	return l.writeLocked(catalogPath, list, current, result, author, revision)
+}
+
+// syncLocked читает журнал и файл каталога и дописывает в журнал расхождения между ними.
+// Возвращает журнал с дописанными правками, каталог и сами дописанные правки.
+func (l *Log) syncLocked(catalogPath string) ([]models.MovieRevision, map[string][]models.Movie, []models.MovieRevision, error) {
+	list, err := l.read()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	current, err := catalog.ReadFile(catalogPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	synced := revisions(replay(list), catalogFields(current), Author{Source: models.SourceFile}, time.Now().UTC(), lastRevision(list))
+	if err := l.append(synced); err != nil {
+		return nil, nil, nil, err
+	}
+	return append(list, synced...), current, synced, nil
+}
+
+// writeLocked сохраняет копию файла каталога, записывает каталог result и дописывает
+// в журнал правки от current к result
+func (l *Log) writeLocked(catalogPath string, list []models.MovieRevision, current, result map[string][]models.Movie, author Author, revertOf int) ([]models.MovieRevision, error) {
+	changes := revisions(catalogFields(current), catalogFields(result), author, time.Now().UTC(), lastRevision(list))
+	if len(changes) == 0 {
+		return changes, nil
+	}
+	for i := range changes {
+		changes[i].RevertOf = revertOf
+	}
+
+	raw, err := os.ReadFile(catalogPath)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать файл каталога: %w", err)
+	}
+	if err := os.WriteFile(catalogPath+".bak", raw, 0644); err != nil {
+		return nil, fmt.Errorf("не удалось сохранить копию каталога: %w", err)
+	}
+	if err := catalog.WriteFile(catalogPath, result); err != nil {
+		return nil, err
+	}
+	if err := l.append(changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// copyCatalog копирует каталог так, чтобы изменения копии не затрагивали оригинал
+func copyCatalog(byCategory map[string][]models.Movie) map[string][]models.Movie {
+	result := make(map[string][]models.Movie, len(byCategory))
+	for category, movies := range byCategory {
+		result[category] = append([]models.Movie(nil), movies...)
+	}
+	return result
+}
+
+// place кладёт фильм id с полями f в каталог: на прежнее место, если категория
+// не изменилась, иначе в конец новой категории. nil удаляет фильм.
+func place(byCategory map[string][]models.Movie, id string, f fields) error {
+	var movie models.Movie
+	if f != nil {
+		var err error
+		if movie, err = f.movie(); err != nil {
+			return fmt.Errorf("не удалось собрать фильм %s из журнала правок: %w", id, err)
+		}
+	}
+	for category, movies := range byCategory {
+		for i := range movies {
+			if movies[i].ID != id {
+				continue
+			}
+			if f != nil && movie.Category == category {
+				movies[i] = movie
+				return nil
+			}
+			byCategory[category] = append(movies[:i:i], movies[i+1:]...)
+			break
+		}
+	}
+	if f != nil {
+		byCategory[movie.Category] = append(byCategory[movie.Category], movie)
+	}
+	return nil
+}
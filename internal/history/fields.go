@@ -0,0 +1,149 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"movie-catalog/internal/models"
+)
+
+// fields — поля фильма в том виде, в каком они записаны в файле каталога
+type fields map[string]json.RawMessage
+
+// movieFields раскладывает фильм на поля. Поле category берётся из ключа группы,
+// под которым фильм лежит в файле.
+func movieFields(movie models.Movie, category string) fields {
+	movie.Category = category
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	// Фильм всегда кодируется в объект
+	encoder.Encode(movie)
+	var f fields
+	json.Unmarshal(buf.Bytes(), &f)
+	for name, value := range f {
+		f[name] = json.RawMessage(bytes.TrimSpace(value))
+	}
+	return f
+}
+
+// catalogFields раскладывает на поля все фильмы каталога
+func catalogFields(byCategory map[string][]models.Movie) map[string]fields {
+	movies := make(map[string]fields)
+	for category, list := range byCategory {
+		for _, movie := range list {
+			movies[movie.ID] = movieFields(movie, category)
+		}
+	}
+	return movies
+}
+
+// movie собирает фильм из полей
+func (f fields) movie() (models.Movie, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return models.Movie{}, err
+	}
+	var movie models.Movie
+	err = json.Unmarshal(data, &movie)
+	return movie, err
+}
+
+// apply возвращает копию полей с внесёнными изменениями
+func (f fields) apply(changes []models.FieldChange) fields {
+	result := make(fields, len(f)+len(changes))
+	for name, value := range f {
+		result[name] = value
+	}
+	for _, change := range changes {
+		if change.New == nil {
+			delete(result, change.Field)
+		} else {
+			result[change.Field] = change.New
+		}
+	}
+	return result
+}
+
+// diffFields сравнивает поля фильма до и после правки; nil означает, что фильма нет
+func diffFields(before, after fields) []models.FieldChange {
+	var changes []models.FieldChange
+	for name, value := range after {
+		if old, ok := before[name]; !ok || !bytes.Equal(old, value) {
+			changes = append(changes, models.FieldChange{Field: name, Old: before[name], New: value})
+		}
+	}
+	for name, old := range before {
+		if _, ok := after[name]; !ok {
+			changes = append(changes, models.FieldChange{Field: name, Old: old})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// replay восстанавливает состояние фильмов после правок revisions
+func replay(revisions []models.MovieRevision) map[string]fields {
+	movies := make(map[string]fields)
+	for _, revision := range revisions {
+		if revision.Action == models.RevisionDelete {
+			delete(movies, revision.MovieID)
+			continue
+		}
+		movies[revision.MovieID] = movies[revision.MovieID].apply(revision.Changes)
+	}
+	return movies
+}
+
+// revisions составляет правки, переводящие фильмы из состояния before в after.
+// Номера правок продолжают номер last.
+func revisions(before, after map[string]fields, author Author, at time.Time, last int) []models.MovieRevision {
+	ids := make([]string, 0, len(after))
+	for id := range after {
+		ids = append(ids, id)
+	}
+	for id := range before {
+		if _, ok := after[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var list []models.MovieRevision
+	for _, id := range ids {
+		old, existed := before[id]
+		current, exists := after[id]
+		changes := diffFields(old, current)
+		if len(changes) == 0 {
+			continue
+		}
+		action := models.RevisionUpdate
+		switch {
+		case !existed:
+			action = models.RevisionCreate
+		case !exists:
+			action = models.RevisionDelete
+		}
+		last++
+		list = append(list, models.MovieRevision{
+			Revision: last,
+			MovieID:  id,
+			Action:   action,
+			Author:   author.Name,
+			Source:   author.Source,
+			At:       at,
+			Changes:  changes,
+		})
+	}
+	return list
+}
+
+// lastRevision возвращает номер последней правки журнала
+func lastRevision(list []models.MovieRevision) int {
+	if len(list) == 0 {
+		return 0
+	}
+	return list[len(list)-1].Revision
+}
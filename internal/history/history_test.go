@@ -0,0 +1,198 @@
+package history
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"movie-catalog/internal/catalog"
+	"movie-catalog/internal/models"
+)
+
+func writeCatalog(t *testing.T, path string, movies ...models.Movie) {
+	t.Helper()
+	byCategory := make(map[string][]models.Movie)
+	for _, movie := range movies {
+		byCategory[movie.Category] = append(byCategory[movie.Category], movie)
+	}
+	if err := catalog.WriteFile(path, byCategory); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readMovie(t *testing.T, path, id string) models.Movie {
+	t.Helper()
+	byCategory, err := catalog.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, movies := range byCategory {
+		for _, movie := range movies {
+			if movie.ID == id {
+				return movie
+			}
+		}
+	}
+	t.Fatalf("фильма %s нет в каталоге", id)
+	return models.Movie{}
+}
+
+func TestSaveKeepsEditsMadeAfterRead(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "movies.json")
+	log := New(filepath.Join(dir, "history.jsonl"))
+	dune := models.Movie{ID: "dune", Title: "Дюна", Year: 2021, Category: "fantasy"}
+	writeCatalog(t, path, dune)
+	if _, err := log.Sync(path); err != nil {
+		t.Fatal(err)
+	}
+
+	draft, err := ReadCatalog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Пока утилита работает, название правят вручную
+	edited := dune
+	edited.Title = "Дюна: часть первая"
+	writeCatalog(t, path, edited)
+	draft.Movies["fantasy"][0].Description = "Пустынная планета"
+
+	saved, err := log.Save(draft, Author{Name: "anna", Source: "description-updater"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readMovie(t, path, "dune"); got.Title != edited.Title || got.Description != "Пустынная планета" {
+		t.Errorf("после Save: %q, %q", got.Title, got.Description)
+	}
+	if len(saved) != 1 || len(saved[0].Changes) != 1 || saved[0].Changes[0].Field != "description" {
+		t.Fatalf("правки утилиты = %+v", saved)
+	}
+
+	revisions, err := log.Movie("dune")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct{ action, source string }{
+		{models.RevisionCreate, models.SourceFile},
+		{models.RevisionUpdate, models.SourceFile},
+		{models.RevisionUpdate, "description-updater"},
+	}
+	if len(revisions) != len(want) {
+		t.Fatalf("журнал = %+v", revisions)
+	}
+	for i, w := range want {
+		if r := revisions[i]; r.Revision != i+1 || r.Action != w.action || r.Source != w.source {
+			t.Errorf("правка %d = %d %s %s, want %s %s", i, r.Revision, r.Action, r.Source, w.action, w.source)
+		}
+	}
+}
+
+func TestRevert(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "movies.json")
+	log := New(filepath.Join(dir, "history.jsonl"))
+	dune := models.Movie{ID: "dune", Title: "Дюна", Year: 2021, Category: "fantasy"}
+	writeCatalog(t, path, dune)
+	if _, err := log.Sync(path); err != nil {
+		t.Fatal(err)
+	}
+	moved := dune
+	moved.Category, moved.Title = "drama", "Дюна 2"
+	writeCatalog(t, path, moved)
+	if _, err := log.Sync(path); err != nil {
+		t.Fatal(err)
+	}
+
+	reverted, err := log.Revert(path, "dune", 1, Author{Name: "admin", Source: models.SourceAPI})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != 1 || reverted[0].RevertOf != 1 || reverted[0].Revision != 3 {
+		t.Fatalf("возврат = %+v", reverted)
+	}
+	if got := readMovie(t, path, "dune"); got.Category != "fantasy" || got.Title != "Дюна" {
+		t.Errorf("после возврата: %s, %q", got.Category, got.Title)
+	}
+
+	// Повторный возврат ничего не меняет
+	if again, err := log.Revert(path, "dune", 1, Author{Source: models.SourceAPI}); err != nil || len(again) != 0 {
+		t.Errorf("повторный возврат = %+v, %v", again, err)
+	}
+	if _, err := log.Revert(path, "dune", 42, Author{Source: models.SourceAPI}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("возврат к несуществующей правке: %v", err)
+	}
+}
+
+func TestSaveRejectsInvalidCatalog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "movies.json")
+	log := New(filepath.Join(dir, "history.jsonl"))
+	writeCatalog(t, path, models.Movie{ID: "dune", Title: "Дюна", Year: 2021, Category: "fantasy"})
+
+	draft, err := ReadCatalog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	draft.Movies["fantasy"][0].Title = ""
+	if _, err := log.Save(draft, Author{Source: "enrich"}); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("ошибка %v, ожидалась ErrInvalid", err)
+	}
+	if got := readMovie(t, path, "dune"); got.Title != "Дюна" {
+		t.Errorf("файл изменён: %q", got.Title)
+	}
+}
+
+func TestMovieFollowsLogChanges(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "movies.json")
+	logPath := filepath.Join(dir, "history.jsonl")
+	server := New(logPath)
+	dune := models.Movie{ID: "dune", Title: "Дюна", Year: 2021, Category: "fantasy"}
+	writeCatalog(t, path, dune)
+	if _, err := server.Sync(path); err != nil {
+		t.Fatal(err)
+	}
+	if revisions, err := server.Movie("dune"); err != nil || len(revisions) != 1 {
+		t.Fatalf("правки %+v, %v", revisions, err)
+	}
+
+	// Утилита дописывает журнал через свой экземпляр Log
+	dune.Title = "Дюна: часть первая"
+	writeCatalog(t, path, dune)
+	if _, err := New(logPath).Sync(path); err != nil {
+		t.Fatal(err)
+	}
+	revisions, err := server.Movie("dune")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 || revisions[1].Revision != 2 {
+		t.Fatalf("после дописывания правки %+v", revisions)
+	}
+
+	// Недописанная строка пропускается, пока не появится её конец
+	f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"revision":3,"movieId":"dune"`)
+	f.Close()
+	if revisions, err := server.Movie("dune"); err != nil || len(revisions) != 2 {
+		t.Fatalf("с недописанной строкой правки %+v, %v", revisions, err)
+	}
+
+	// Файл журнала заменили более коротким — он перечитывается целиком
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := data[:bytes.IndexByte(data, '\n')+1]
+	if err := os.WriteFile(logPath, first, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if revisions, err := server.Movie("dune"); err != nil || len(revisions) != 1 {
+		t.Fatalf("после замены файла правки %+v, %v", revisions, err)
+	}
+}
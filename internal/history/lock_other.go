@@ -0,0 +1,12 @@
+//go:build !unix
+
+package history
+
+import "os"
+
+// На системах без flock журнал не защищён от одновременной записи
+// сервером и утилитами
+
+func lockFile(*os.File) error { return nil }
+
+func unlockFile(*os.File) error { return nil }
@@ -0,0 +1,221 @@
+// Package history ведёт журнал правок фильмов каталога: кто, когда и какие поля изменил.
+// Журнал хранится в файле JSON Lines и только дописывается; по нему восстанавливается
+// состояние фильма после любой правки.
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"movie-catalog/internal/models"
+)
+
+var (
+	// ErrNotFound — в журнале нет такой правки фильма
+	ErrNotFound = errors.New("ревизия не найдена")
+	// ErrInvalid — каталог после правки или возврата к ревизии не прошёл проверку
+	ErrInvalid = errors.New("каталог после правки не прошёл проверку")
+)
+
+// Author — кто и чем изменил каталог
+type Author struct {
+	// Name — имя автора, например пользователь ОС или администратор; может быть пустым
+	Name string
+	// Source — чем внесена правка: models.SourceFile, models.SourceAPI или имя утилиты
+	Source string
+}
+
+// Log — журнал правок фильмов. Журналом и файлом каталога одновременно пользуются
+// сервер и утилиты, поэтому каждое изменение делается под блокировкой файла
+// рядом с журналом. Разобранный журнал хранится в памяти и дочитывается по мере
+// роста файла.
+type Log struct {
+	path string
+
+	mu    sync.Mutex
+	cache cache
+}
+
+// cache — разобранная часть журнала. Журнал только дописывается, поэтому при росте
+// файла разбирается лишь новый хвост. Если файл стал короче, изменился без роста
+// или перед прочитанным местом нет конца строки, он перечитывается целиком.
+type cache struct {
+	// offset — сколько байт разобрано: до конца последней полной строки
+	offset int64
+	// lines — сколько строк разобрано, для номеров строк в ошибках
+	lines int
+	// size и modTime — размер и время изменения файла при последнем чтении
+	size    int64
+	modTime time.Time
+
+	revisions []models.MovieRevision
+	// byMovie — номера правок каждого фильма в revisions
+	byMovie map[string][]int
+}
+
+// New возвращает журнал в файле path; файл появится при первой правке
+func New(path string) *Log {
+	return &Log{path: path}
+}
+
+// Path возвращает путь к файлу журнала
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Movie возвращает правки фильма id от старых к новым
+func (l *Log) Movie(id string) ([]models.MovieRevision, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.refresh(); err != nil {
+		return nil, err
+	}
+	var movie []models.MovieRevision
+	for _, i := range l.cache.byMovie[id] {
+		movie = append(movie, l.cache.revisions[i])
+	}
+	return movie, nil
+}
+
+// read возвращает все правки журнала. Вызывающий не должен изменять список.
+func (l *Log) read() ([]models.MovieRevision, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.refresh(); err != nil {
+		return nil, err
+	}
+	// Ёмкость обрезана, чтобы append у вызывающего не испортил кэш
+	n := len(l.cache.revisions)
+	return l.cache.revisions[:n:n], nil
+}
+
+// refresh дочитывает журнал в кэш. Строка без перевода строки в конце файла
+// пропускается: её ещё дописывает другой процесс.
+func (l *Log) refresh() error {
+	f, err := os.Open(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		l.cache = cache{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать журнал правок: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать журнал правок: %w", err)
+	}
+	if info.Size() == l.cache.size && info.ModTime().Equal(l.cache.modTime) {
+		return nil
+	}
+
+	c := l.cache
+	if info.Size() <= c.size || !endsLine(f, c.offset) {
+		c = cache{}
+	}
+	if _, err := f.Seek(c.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("не удалось прочитать журнал правок: %w", err)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать журнал правок: %w", err)
+	}
+
+	// Новые правки разбираются в копию: при ошибке кэш остаётся прежним
+	end := bytes.LastIndexByte(data, '\n') + 1
+	revisions := c.revisions[:len(c.revisions):len(c.revisions)]
+	byMovie := make(map[string][]int, len(c.byMovie))
+	for id, list := range c.byMovie {
+		byMovie[id] = list[:len(list):len(list)]
+	}
+	for rest := data[:end]; len(rest) > 0; {
+		i := bytes.IndexByte(rest, '\n')
+		line := rest[:i]
+		rest = rest[i+1:]
+		c.lines++
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var revision models.MovieRevision
+		if err := json.Unmarshal(line, &revision); err != nil {
+			return fmt.Errorf("ошибка в журнале правок, строка %d: %w", c.lines, err)
+		}
+		byMovie[revision.MovieID] = append(byMovie[revision.MovieID], len(revisions))
+		revisions = append(revisions, revision)
+	}
+
+	c.offset += int64(end)
+	c.size, c.modTime = info.Size(), info.ModTime()
+	c.revisions, c.byMovie = revisions, byMovie
+	l.cache = c
+	return nil
+}
+
+// endsLine сообщает, что в файле перед offset заканчивается строка
+func endsLine(f *os.File, offset int64) bool {
+	if offset == 0 {
+		return true
+	}
+	b := make([]byte, 1)
+	_, err := f.ReadAt(b, offset-1)
+	return err == nil && b[0] == '\n'
+}
+
+// append дописывает правки в конец журнала одной записью
+func (l *Log) append(revisions []models.MovieRevision) error {
+	if len(revisions) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	for _, revision := range revisions {
+		if err := encoder.Encode(revision); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("не удалось создать каталог журнала правок: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("не удалось открыть журнал правок: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("не удалось дописать журнал правок: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("не удалось дописать журнал правок: %w", err)
+	}
+	return f.Close()
+}
+
+// lock захватывает блокировку журнала и каталога, общую для всех процессов
+func (l *Log) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return nil, fmt.Errorf("не удалось создать каталог журнала правок: %w", err)
+	}
+	f, err := os.OpenFile(l.path+".lock", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть файл блокировки журнала: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("не удалось заблокировать журнал правок: %w", err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
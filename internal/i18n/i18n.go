@@ -0,0 +1,132 @@
+// Package i18n хранит каталоги сообщений интерфейса и выбирает язык пользователя
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Default — язык по умолчанию; на него откатываются отсутствующие переводы
+const Default = "ru"
+
+// Supported — поддерживаемые языки; первый используется по умолчанию
+var Supported = []string{"ru", "en"}
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Каталоги сообщений: язык → ключ → текст
+var catalogs = mustLoad()
+
+var matcher = newMatcher()
+
+func mustLoad() map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, locale := range Supported {
+		data, err := localeFiles.ReadFile(path.Join("locales", locale+".json"))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: нет каталога сообщений %s: %v", locale, err))
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: некорректный каталог сообщений %s: %v", locale, err))
+		}
+		result[locale] = messages
+	}
+	return result
+}
+
+func newMatcher() language.Matcher {
+	tags := make([]language.Tag, len(Supported))
+	for i, locale := range Supported {
+		tags[i] = language.Make(locale)
+	}
+	return language.NewMatcher(tags)
+}
+
+// Supports сообщает, поддерживается ли язык
+func Supports(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Match выбирает поддерживаемый язык по заголовку Accept-Language
+func Match(acceptLanguage string) string {
+	tags, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	_, index, _ := matcher.Match(tags...)
+	return Supported[index]
+}
+
+// Lookup возвращает сообщение на языке locale с откатом на язык по умолчанию
+func Lookup(locale, key string) (string, bool) {
+	if text, ok := catalogs[locale][key]; ok {
+		return text, true
+	}
+	text, ok := catalogs[Default][key]
+	return text, ok
+}
+
+// T возвращает сообщение по ключу на языке locale. Аргументы подставляются
+// через fmt.Sprintf; для неизвестного ключа возвращается сам ключ.
+func T(locale, key string, args ...interface{}) string {
+	text, ok := Lookup(locale, key)
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// CategoryName возвращает название категории на языке locale или сам ключ, если он неизвестен
+func CategoryName(locale, key string) string {
+	if name, ok := Lookup(locale, "category."+key); ok {
+		return name
+	}
+	return key
+}
+
+// TagName возвращает название метки на языке locale или саму метку, если перевода нет
+func TagName(locale, key string) string {
+	if name, ok := Lookup(locale, "tag."+key); ok {
+		return name
+	}
+	return key
+}
+
+// Messages возвращает сообщения на языке locale, ключи которых начинаются
+// с одного из префиксов, например для передачи в JavaScript
+func Messages(locale string, prefixes ...string) map[string]string {
+	result := make(map[string]string)
+	for _, lang := range []string{Default, locale} {
+		for key, text := range catalogs[lang] {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(key, prefix) {
+					result[key] = text
+					break
+				}
+			}
+		}
+	}
+	return result
+}
+
+type localeKey struct{}
+
+// WithLocale сохраняет язык запроса в контексте
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext возвращает язык запроса или пустую строку, если он не выбран
+func FromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
@@ -0,0 +1,59 @@
+package jsonfile
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Value — данные типа T, которые хранятся в JSON-файле и меняются из нескольких
+// горутин. Изменение готовит новую копию данных, записывает её в файл целиком и
+// только после успешной записи делает текущей: читатели не видят несохранённых
+// изменений, а при ошибке записи данные остаются прежними.
+type Value[T any] struct {
+	path string
+	// what — что хранится в файле, для сообщений об ошибках, например «подборки»
+	what string
+
+	mu   sync.RWMutex
+	data T
+}
+
+// Open читает данные из файла path. Если файла нет, данные — нулевое значение T,
+// а файл появится при первом изменении.
+func Open[T any](path, what string) (*Value[T], error) {
+	v := &Value[T]{path: path, what: what}
+	if _, err := Read(path, &v.data); err != nil {
+		return nil, fmt.Errorf("не удалось прочитать %s: %w", what, err)
+	}
+	return v, nil
+}
+
+// Read вызывает fn с текущими данными под блокировкой на чтение.
+// fn не должна изменять данные или сохранять на них ссылки.
+func (v *Value[T]) Read(fn func(data T)) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	fn(v.data)
+}
+
+// Update вызывает change с текущими данными под блокировкой на запись. change
+// не изменяет их, а возвращает новую копию; ошибка change возвращается как есть,
+// и файл не записывается. Если committed не nil, он вызывается с новыми данными
+// после записи под той же блокировкой, поэтому видит изменения в порядке записи.
+func (v *Value[T]) Update(change func(data T) (T, error), committed func(data T)) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	next, err := change(v.data)
+	if err != nil {
+		return err
+	}
+	if err := Write(v.path, next); err != nil {
+		return fmt.Errorf("не удалось сохранить %s: %w", v.what, err)
+	}
+	v.data = next
+	if committed != nil {
+		committed(next)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Package logger настраивает структурированное JSON-логирование через log/slog
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Ключ контекста для идентификатора запроса
+type requestIDKey struct{}
+
+// New создаёт JSON-логгер с указанным уровнем (debug, info, warn, error).
+// Неизвестный уровень трактуется как info.
+// В каждую запись добавляется request_id, если он есть в контексте.
+func New(w io.Writer, level string) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: ParseLevel(level)})
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// ParseLevel преобразует строковое имя уровня в slog.Level
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithRequestID сохраняет идентификатор запроса в контексте
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID возвращает идентификатор запроса из контекста или пустую строку
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// contextHandler дополняет записи данными из контекста
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
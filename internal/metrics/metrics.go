@@ -0,0 +1,106 @@
+// Package metrics содержит метрики Prometheus сервера каталога
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "moviecatalog"
+
+var (
+	// HTTPRequests — количество обработанных запросов по маршруту, методу и статусу
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Количество обработанных HTTP-запросов.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPDuration — время обработки запросов по маршруту и методу
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Время обработки HTTP-запросов.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	// HTTPInFlight — количество запросов, обрабатываемых в данный момент
+	HTTPInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "Количество HTTP-запросов, обрабатываемых в данный момент.",
+	})
+
+	// CatalogReloads — попытки перезагрузки каталога с результатом success или failure
+	CatalogReloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "catalog_reloads_total",
+		Help:      "Количество перезагрузок каталога по результату.",
+	}, []string{"result"})
+
+	// TemplateRenderErrors — ошибки рендеринга HTML-шаблонов
+	TemplateRenderErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "template_render_errors_total",
+		Help:      "Количество ошибок рендеринга шаблонов.",
+	}, []string{"template"})
+)
+
+// Registry — реестр метрик сервера. Отдельный реестр вместо глобального,
+// чтобы на /metrics попадали только явно зарегистрированные метрики.
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		HTTPRequests,
+		HTTPDuration,
+		HTTPInFlight,
+		CatalogReloads,
+		TemplateRenderErrors,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// ObserveReload учитывает результат перезагрузки каталога
+func ObserveReload(err error) {
+	if err != nil {
+		CatalogReloads.WithLabelValues("failure").Inc()
+		return
+	}
+	CatalogReloads.WithLabelValues("success").Inc()
+}
+
+// RegisterCatalogSize регистрирует метрику размера каталога по категориям.
+// sizes вызывается при каждом сборе метрик.
+func RegisterCatalogSize(sizes func() map[string]int) {
+	Registry.MustRegister(&catalogSizeCollector{sizes: sizes})
+}
+
+var catalogSizeDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "catalog", "movies"),
+	"Количество фильмов в каталоге по категориям.",
+	[]string{"category"}, nil,
+)
+
+type catalogSizeCollector struct {
+	sizes func() map[string]int
+}
+
+func (c *catalogSizeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- catalogSizeDesc
+}
+
+func (c *catalogSizeCollector) Collect(ch chan<- prometheus.Metric) {
+	for category, size := range c.sizes() {
+		ch <- prometheus.MustNewConstMetric(catalogSizeDesc, prometheus.GaugeValue, float64(size), category)
+	}
+}
+
+// Handler отдаёт метрики в текстовом формате Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
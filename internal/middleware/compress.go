@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// Типы содержимого, которые сжимаются на лету
+var compressibleTypes = map[string]bool{
+	"text/html":            true,
+	"application/json":     true,
+	"application/rss+xml":  true,
+	"application/atom+xml": true,
+	"application/xml":      true,
+}
+
+// Compress сжимает HTML, JSON, ленты и карту сайта в brotli или gzip в зависимости от Accept-Encoding клиента.
+// Статические файлы обрабатываются отдельно через Precompressed и на лету не сжимаются.
+func Compress() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), encodingBrotli, encodingGzip)
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: c.Writer, encoding: encoding}
+		c.Writer = cw
+		defer cw.Close()
+
+		c.Next()
+	}
+}
+
+// compressWriter откладывает решение о сжатии до первой записи тела,
+// когда уже известен Content-Type ответа
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	encoder  io.WriteCloser
+	decided  bool
+}
+
+func (w *compressWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+
+	header := w.Header()
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if !compressibleTypes[mediaType] {
+		return
+	}
+	addVary(header, "Accept-Encoding")
+	if header.Get("Content-Encoding") != "" {
+		return
+	}
+	// Ответ по Range и файл с известной длиной (http.ServeContent) отдаются как есть:
+	// Content-Range и Content-Length описывают несжатые байты
+	if w.Status() == http.StatusPartialContent || header.Get("Content-Range") != "" || header.Get("Content-Length") != "" {
+		return
+	}
+
+	header.Set("Content-Encoding", w.encoding)
+	switch w.encoding {
+	case encodingBrotli:
+		w.encoder = brotli.NewWriterLevel(w.ResponseWriter, brotli.DefaultCompression)
+	case encodingGzip:
+		w.encoder, _ = gzip.NewWriterLevel(w.ResponseWriter, gzip.DefaultCompression)
+	}
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	w.decide()
+	if w.encoder == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+	return w.encoder.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush сбрасывает сжатые данные клиенту
+func (w *compressWriter) Flush() {
+	if f, ok := w.encoder.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Close завершает поток сжатия
+func (w *compressWriter) Close() {
+	if w.encoder != nil {
+		w.encoder.Close()
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestCompressSkipsRanges проверяет, что часть файла по Range отдаётся без сжатия:
+// Content-Range и Content-Length описывают несжатые байты
+func TestCompressSkipsRanges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := strings.Repeat(`{"id": "inception", "title": "Начало"}`, 100)
+	router := gin.New()
+	router.Use(Compress())
+	router.GET("/movies.json", func(c *gin.Context) {
+		http.ServeContent(c.Writer, c.Request, "movies.json", time.Time{}, strings.NewReader(body))
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/movies.json", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	request.Header.Set("Range", "bytes=0-99")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusPartialContent {
+		t.Fatalf("статус %d, ожидался 206", recorder.Code)
+	}
+	if encoding := recorder.Header().Get("Content-Encoding"); encoding != "" {
+		t.Errorf("часть файла сжата в %s", encoding)
+	}
+	if !bytes.Equal(recorder.Body.Bytes(), []byte(body[:100])) {
+		t.Errorf("тело %q, ожидались первые 100 байт", recorder.Body)
+	}
+
+	// Файл целиком с известной длиной тоже не сжимается на лету
+	request = httptest.NewRequest(http.MethodGet, "/movies.json", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if encoding := recorder.Header().Get("Content-Encoding"); encoding != "" || recorder.Body.String() != body {
+		t.Errorf("файл с Content-Length сжат в %q", encoding)
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated помечает устаревший маршрут: заголовок Deprecation (RFC 9745) с датой,
+// с которой маршрут устарел, и Link на маршрут-замену, если successor вернул непустой путь
+func Deprecated(since time.Time, successor func(c *gin.Context) string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(since.Unix(), 10)
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		if successor != nil {
+			if path := successor(c); path != "" {
+				c.Header("Link", "<"+path+`>; rel="successor-version"`)
+			}
+		}
+		c.Next()
+	}
+}
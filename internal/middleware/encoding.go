@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Поддерживаемые кодировки сжатия
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+// negotiateEncoding выбирает кодировку из offers по заголовку Accept-Encoding.
+// При равных q-значениях побеждает кодировка, стоящая раньше в offers.
+// Пустая строка означает, что ответ нужно отдать без сжатия.
+func negotiateEncoding(acceptEncoding string, offers ...string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		q := 1.0
+		if key, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(key) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+		weights[name] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q, ok := weights[offer]
+		if !ok {
+			q, ok = weights["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// addVary добавляет значение в заголовок Vary, не дублируя его
+func addVary(header http.Header, value string) {
+	for _, v := range header.Values("Vary") {
+		for _, item := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(item), value) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", value)
+}
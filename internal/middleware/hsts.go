@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HSTS добавляет заголовок Strict-Transport-Security к ответам, отданным по HTTPS.
+// По обычному HTTP заголовок не отправляется: браузеры его там игнорируют.
+// includeSubdomains добавляет директиву includeSubDomains.
+func HSTS(maxAge time.Duration, includeSubdomains bool) gin.HandlerFunc {
+	value := "max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+	if includeSubdomains {
+		value += "; includeSubDomains"
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.TLS != nil && maxAge > 0 {
+			c.Header("Strict-Transport-Security", value)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/i18n"
+)
+
+// LocaleCookie — cookie, в которой запоминается выбранный пользователем язык
+const LocaleCookie = "lang"
+
+// Ключ gin.Context, под которым хранится язык запроса
+const localeKey = "locale"
+
+// Срок хранения cookie с языком — год
+const localeCookieMaxAge = 365 * 24 * 60 * 60
+
+// DetectLocale выбирает язык запроса: по префиксу маршрута (/en/...), параметру ?lang=
+// (он же запоминается в cookie), cookie lang или заголовку Accept-Language.
+// Язык доступен через Locale и i18n.FromContext.
+func DetectLocale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := localeFromPath(c.FullPath())
+		if locale == "" {
+			// Без префикса ответ зависит от cookie и Accept-Language
+			addVary(c.Writer.Header(), "Cookie")
+			addVary(c.Writer.Header(), "Accept-Language")
+			if lang := c.Query("lang"); i18n.Supports(lang) {
+				locale = lang
+				c.SetSameSite(http.SameSiteLaxMode)
+				c.SetCookie(LocaleCookie, lang, localeCookieMaxAge, "/", "", c.Request.TLS != nil, true)
+			}
+		}
+		if locale == "" {
+			if lang, err := c.Cookie(LocaleCookie); err == nil && i18n.Supports(lang) {
+				locale = lang
+			}
+		}
+		if locale == "" {
+			locale = i18n.Match(c.GetHeader("Accept-Language"))
+		}
+
+		c.Set(localeKey, locale)
+		c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
+		c.Next()
+	}
+}
+
+// Locale возвращает язык текущего запроса
+func Locale(c *gin.Context) string {
+	if locale := c.GetString(localeKey); locale != "" {
+		return locale
+	}
+	return i18n.Default
+}
+
+// LocalePrefix возвращает префикс маршрутов для языка: пустой для языка по умолчанию
+// и "/en" для остальных
+func LocalePrefix(locale string) string {
+	if locale == i18n.Default || !i18n.Supports(locale) {
+		return ""
+	}
+	return "/" + locale
+}
+
+// localeFromPath извлекает язык из префикса шаблона маршрута
+func localeFromPath(fullPath string) string {
+	for _, locale := range i18n.Supported {
+		prefix := LocalePrefix(locale)
+		if prefix != "" && (fullPath == prefix || strings.HasPrefix(fullPath, prefix+"/")) {
+			return locale
+		}
+	}
+	return ""
+}
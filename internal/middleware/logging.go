@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Logger пишет структурированную запись о каждом запросе.
+// Ответы 5xx логируются с уровнем error, 4xx — warn, остальные — info.
+func Logger(log *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		query := c.Request.URL.RawQuery
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("size", c.Writer.Size()),
+		}
+		if query != "" {
+			attrs = append(attrs, slog.String("query", query))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+
+		log.LogAttrs(c.Request.Context(), level, "HTTP-запрос", attrs...)
+	}
+}
+
+// Recovery перехватывает панику в обработчике, логирует её со стеком вызовов и отвечает 500
+func Recovery(log *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log.ErrorContext(c.Request.Context(), "Паника при обработке запроса",
+					"panic", recovered,
+					"method", c.Request.Method,
+					"path", c.Request.URL.Path,
+					"stack", string(debug.Stack()),
+				)
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+		c.Next()
+	}
+}
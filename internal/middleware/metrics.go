@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/metrics"
+)
+
+// Metrics считает запросы, время их обработки и количество запросов в работе.
+// Запросы к незарегистрированным маршрутам объединяются под меткой "unmatched",
+// чтобы произвольные URL не раздували число временных рядов.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		metrics.HTTPInFlight.Inc()
+		defer metrics.HTTPInFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPRequests.WithLabelValues(route, c.Request.Method, strconv.Itoa(c.Writer.Status())).Inc()
+		metrics.HTTPDuration.WithLabelValues(route, c.Request.Method).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Расширения предварительно сжатых файлов для каждой кодировки
+var precompressedExt = map[string]string{
+	encodingBrotli: ".br",
+	encodingGzip:   ".gz",
+}
+
+// Precompressed отдаёт заранее сжатые версии статических файлов (.br, .gz),
+// если клиент их принимает и они не старше исходного файла.
+// prefix — URL-префикс группы статики, root — каталог на диске.
+// Если подходящей версии нет, запрос передаётся следующему обработчику.
+func Precompressed(prefix, root string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		relPath := strings.TrimPrefix(path.Clean(c.Request.URL.Path), prefix)
+		filePath := filepath.Join(root, filepath.FromSlash(path.Clean("/"+relPath)))
+
+		original, err := os.Stat(filePath)
+		if err != nil || original.IsDir() {
+			c.Next()
+			return
+		}
+
+		var offers []string
+		for _, encoding := range []string{encodingBrotli, encodingGzip} {
+			variant, err := os.Stat(filePath + precompressedExt[encoding])
+			if err == nil && !variant.ModTime().Before(original.ModTime()) {
+				offers = append(offers, encoding)
+			}
+		}
+		if len(offers) == 0 {
+			c.Next()
+			return
+		}
+		addVary(c.Writer.Header(), "Accept-Encoding")
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), offers...)
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		variantFile, err := os.Open(filePath + precompressedExt[encoding])
+		if err != nil {
+			c.Next()
+			return
+		}
+		defer variantFile.Close()
+
+		contentType := mime.TypeByExtension(filepath.Ext(filePath))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Encoding", encoding)
+		http.ServeContent(c.Writer, c.Request, filepath.Base(filePath), original.ModTime(), variantFile)
+		c.Abort()
+	}
+}
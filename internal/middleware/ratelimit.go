@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+
+	"movie-catalog/internal/config"
+)
+
+// Через сколько неактивности забывается состояние клиента
+const visitorTTL = 10 * time.Minute
+
+// IPRateLimiter ограничивает частоту запросов с одного IP-адреса.
+// Адрес клиента определяется через gin.Context.ClientIP, поэтому
+// X-Forwarded-For учитывается только от доверенных прокси роутера.
+type IPRateLimiter struct {
+	limit config.RateLimit
+
+	mu          sync.Mutex
+	visitors    map[string]*visitor
+	lastCleanup time.Time
+}
+
+type visitor struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewIPRateLimiter создаёт ограничитель с заданными параметрами
+func NewIPRateLimiter(limit config.RateLimit) *IPRateLimiter {
+	return &IPRateLimiter{
+		limit:       limit,
+		visitors:    make(map[string]*visitor),
+		lastCleanup: time.Now(),
+	}
+}
+
+// Handler возвращает middleware, которое при превышении лимита ставит Retry-After
+// и прерывает запрос; ответ 429 пишет reject. Если лимит не задан, запросы
+// пропускаются без проверки.
+func (l *IPRateLimiter) Handler(reject gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !l.limit.Enabled() {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		reservation := l.limiter(c.ClientIP(), now).ReserveN(now, 1)
+		if delay := reservation.DelayFrom(now); delay > 0 {
+			reservation.CancelAt(now)
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			reject(c)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// limiter возвращает token bucket клиента, попутно удаляя давно неактивных
+func (l *IPRateLimiter) limiter(ip string, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastCleanup) > visitorTTL {
+		for key, v := range l.visitors {
+			if now.Sub(v.lastSeen) > visitorTTL {
+				delete(l.visitors, key)
+			}
+		}
+		l.lastCleanup = now
+	}
+
+	v, ok := l.visitors[ip]
+	if !ok {
+		v = &visitor{limiter: rate.NewLimiter(rate.Limit(l.limit.RPS), l.limit.Burst)}
+		l.visitors[ip] = v
+	}
+	v.lastSeen = now
+	return v.limiter
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+
+	"movie-catalog/internal/logger"
+)
+
+// HeaderRequestID — заголовок с идентификатором запроса
+const HeaderRequestID = "X-Request-ID"
+
+// Максимальная длина принимаемого от клиента идентификатора
+const maxRequestIDLength = 128
+
+// RequestID присваивает запросу идентификатор: берёт корректный X-Request-ID из запроса
+// (например, от обратного прокси) или генерирует новый. Идентификатор возвращается
+// в заголовке ответа и сохраняется в контексте запроса для логов.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(HeaderRequestID)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Header(HeaderRequestID, requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+// validRequestID допускает только печатные ASCII-символы без пробелов,
+// чтобы чужой идентификатор не ломал логи и заголовки
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] <= ' ' || requestID[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
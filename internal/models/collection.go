@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Collection — подборка фильмов, например «Нолан» или «Для вечера с семьёй»
+type Collection struct {
+	// Slug — идентификатор в адресе /collection/{slug}: латиница, цифры и дефисы
+	Slug        string `json:"slug"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// MovieIDs — фильмы подборки в порядке показа
+	MovieIDs []string `json:"movieIds"`
+	// Cover — адрес обложки; если не задан, показывается постер первого фильма
+	Cover     string    `json:"cover,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CollectionDetails — подборка с фильмами, которые есть в каталоге
+type CollectionDetails struct {
+	Collection
+	Movies []Movie `json:"movies"`
+}
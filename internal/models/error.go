@@ -0,0 +1,6 @@
+package models
+
+// ErrorResponse — тело ответа API с ошибкой
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -1,13 +1,117 @@
 package models
 
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"movie-catalog/internal/i18n"
+)
+
 // Movie представляет информацию о фильме
 type Movie struct {
-	ID          string `json:"id"`
-	Title       string `json:"title"`
-	Year        int    `json:"year"`
-	Category    string `json:"category"`
-	Description string `json:"description"`
-	ImagePath   string `json:"imagePath"`
+	ID              string `json:"id"`
+	Title           string `json:"title"`
+	Year            int    `json:"year"`
+	Category        string `json:"category"`
+	Description     string `json:"description"`
+	ImagePath       string `json:"imagePath"`
+	FullDescription string `json:"fullDescription"`
+	Link            string `json:"link"`
+	// OriginalTitle — название на языке оригинала. Это и следующие поля до Tagline
+	// необязательны: в старых файлах каталога их нет
+	OriginalTitle string `json:"originalTitle,omitempty"`
+	// Runtime — продолжительность в минутах
+	Runtime   int      `json:"runtime,omitempty"`
+	Directors []string `json:"directors,omitempty"`
+	// Cast — актёры главных ролей
+	Cast      []string `json:"cast,omitempty"`
+	Countries []string `json:"countries,omitempty"`
+	// AgeRating — возрастное ограничение: 0+, 6+, 12+, 16+ или 18+
+	AgeRating string `json:"ageRating,omitempty"`
+	Tagline   string `json:"tagline,omitempty"`
+	// Tags — свободные метки тем, которые не выражаются одной категорией,
+	// например "time-travel" или "true-story": латиница, цифры и дефисы
+	Tags []string `json:"tags,omitempty"`
+	// Credits — ссылки на людей из people.json с их ролями. Заполняются при загрузке
+	// каталога по именам из Directors и Cast, в файле каталога не хранятся
+	Credits []Credit `json:"credits,omitempty"`
+	// ExternalIDs — идентификаторы фильма во внешних сервисах: kinopoisk, imdb, tmdb.
+	// Идентификатор Кинопоиска, если он не указан, берётся из Link
+	ExternalIDs map[string]string `json:"externalIds,omitempty"`
+	// CreatedAt — когда фильм добавлен в каталог
+	CreatedAt time.Time `json:"createdAt"`
+	// Translations — переводы названия и описаний по языкам, например "en".
+	// Основные поля содержат русский текст
+	Translations map[string]MovieTranslation `json:"translations,omitempty"`
+}
+
+// MovieTranslation — перевод текстовых полей фильма; пустые поля берутся из русской версии
+type MovieTranslation struct {
+	Title           string `json:"title,omitempty"`
+	Description     string `json:"description,omitempty"`
+	FullDescription string `json:"fullDescription,omitempty"`
+	Tagline         string `json:"tagline,omitempty"`
+}
+
+// AgeRatings — допустимые возрастные ограничения
+var AgeRatings = []string{"0+", "6+", "12+", "16+", "18+"}
+
+// MinAge возвращает возраст из ограничения AgeRating, например 16 для "16+";
+// false — ограничение не указано или некорректно
+func (m Movie) MinAge() (int, bool) {
+	age, err := strconv.Atoi(strings.TrimSuffix(m.AgeRating, "+"))
+	if err != nil || !strings.HasSuffix(m.AgeRating, "+") {
+		return 0, false
+	}
+	return age, true
+}
+
+// Localized возвращает копию фильма с текстами на языке locale,
+// подставляя русские значения вместо отсутствующих переводов
+func (m Movie) Localized(locale string) Movie {
+	tr, ok := m.Translations[locale]
+	if !ok {
+		return m
+	}
+	if tr.Title != "" {
+		m.Title = tr.Title
+	}
+	if tr.Description != "" {
+		m.Description = tr.Description
+	}
+	if tr.FullDescription != "" {
+		m.FullDescription = tr.FullDescription
+	}
+	if tr.Tagline != "" {
+		m.Tagline = tr.Tagline
+	}
+	return m
+}
+
+// Category представляет категорию фильмов
+type Category struct {
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Tag представляет метку с количеством фильмов
+type Tag struct {
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// SimilarMovie — фильм с оценкой сходства от 0 до 1
+type SimilarMovie struct {
+	Movie
+	Score float64 `json:"score"`
+}
+
+// CategoryName возвращает название категории на русском или сам ключ, если он неизвестен
+func CategoryName(key string) string {
+	return i18n.CategoryName(i18n.Default, key)
 }
 
 // GetCategories возвращает список всех категорий фильмов
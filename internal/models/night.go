@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Способы голосования на киновечере
+const (
+	// VotingApproval — каждый отмечает все фильмы, которые готов смотреть;
+	// побеждает фильм с наибольшим числом отметок
+	VotingApproval = "approval"
+	// VotingRanked — каждый расставляет фильмы по предпочтению; победитель
+	// определяется мгновенным вторым туром
+	VotingRanked = "ranked"
+)
+
+// MovieNight — голосование компании за фильм на вечер
+type MovieNight struct {
+	// ID — случайный идентификатор из ссылки, которой делятся с участниками
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Method string `json:"method"`
+	// MovieIDs — фильмы-кандидаты в порядке, заданном создателем
+	MovieIDs []string `json:"movieIds"`
+	// Deadline — когда голосование завершится само
+	Deadline  time.Time `json:"deadline"`
+	CreatedAt time.Time `json:"createdAt"`
+	// ClosedAt — когда голосование завершилось; пусто, пока оно идёт
+	ClosedAt *time.Time `json:"closedAt,omitempty"`
+	// Winner — фильм-победитель; пусто, пока голосование идёт или если никто не проголосовал
+	Winner string `json:"winner,omitempty"`
+}
+
+// Closed сообщает, завершено ли голосование
+func (n MovieNight) Closed() bool {
+	return n.ClosedAt != nil
+}
+
+// NightBallot — бюллетень участника. При голосовании approval Choices — все
+// подходящие фильмы, при ranked — фильмы от самого желанного; не названные
+// фильмы считаются худшими.
+type NightBallot struct {
+	// Name — имя, под которым участника видят остальные; необязательно
+	Name      string    `json:"name,omitempty"`
+	Choices   []string  `json:"choices"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// NightTally — голоса за фильм
+type NightTally struct {
+	MovieID string `json:"movieId"`
+	Votes   int    `json:"votes"`
+}
+
+// NightRound — тур подсчёта при голосовании ranked: голоса за оставшиеся фильмы
+// по первым предпочтениям и выбывшие после тура фильмы
+type NightRound struct {
+	Tally      []NightTally `json:"tally"`
+	Eliminated []string     `json:"eliminated,omitempty"`
+}
+
+// NightResults — текущее состояние голосования
+type NightResults struct {
+	Night MovieNight `json:"night"`
+	// Voters — сколько участников проголосовало
+	Voters int `json:"voters"`
+	// Participants — имена проголосовавших; безымянные участники учитываются только в Voters
+	Participants []string `json:"participants"`
+	// Tally — отметки за фильмы (approval) или первые предпочтения (ranked), лидеры первыми
+	Tally []NightTally `json:"tally"`
+	// Rounds — туры мгновенного второго тура, только для ranked
+	Rounds []NightRound `json:"rounds,omitempty"`
+	// Leader — фильм, который победил бы, если бы голосование завершилось сейчас
+	Leader string `json:"leader,omitempty"`
+}
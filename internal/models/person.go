@@ -0,0 +1,46 @@
+package models
+
+// Роли людей в фильме
+const (
+	RoleDirector = "director"
+	RoleActor    = "actor"
+)
+
+// Person — режиссёр или актёр из people.json
+type Person struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Photo — адрес фотографии, необязательно
+	Photo string `json:"photo,omitempty"`
+	// Names — имя на других языках, например {"en": "Christopher Nolan"}
+	Names map[string]string `json:"names,omitempty"`
+}
+
+// Localized возвращает копию с именем на языке locale, если оно известно
+func (p Person) Localized(locale string) Person {
+	if name := p.Names[locale]; name != "" {
+		p.Name = name
+	}
+	return p
+}
+
+// Credit — участие человека в фильме
+type Credit struct {
+	PersonID string `json:"personId"`
+	// Name — имя так, как оно записано в фильме
+	Name string `json:"name"`
+	// Role — director или actor
+	Role string `json:"role"`
+}
+
+// PersonMovie — фильм из фильмографии человека с его ролями в нём
+type PersonMovie struct {
+	Movie
+	Roles []string `json:"roles"`
+}
+
+// PersonDetails — человек и его фильмы в каталоге, от новых к старым
+type PersonDetails struct {
+	Person
+	Movies []PersonMovie `json:"movies"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Границы оценки фильма
+const (
+	MinRating = 1
+	MaxRating = 10
+)
+
+// Источники рекомендаций
+const (
+	// ReasonCollaborative — фильм высоко оценили зрители с похожими оценками
+	ReasonCollaborative = "collaborative"
+	// ReasonSimilar — фильм похож по содержанию на понравившийся или отложенный
+	ReasonSimilar = "similar"
+	// ReasonPopular — фильм из самых высоко оценённых, когда о зрителе ещё ничего не известно
+	ReasonPopular = "popular"
+)
+
+// UserRating — оценка фильма зрителем от MinRating до MaxRating
+type UserRating struct {
+	MovieID   string    `json:"movieId"`
+	Rating    int       `json:"rating"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// WatchlistItem — фильм, отложенный зрителем на потом
+type WatchlistItem struct {
+	MovieID string    `json:"movieId"`
+	AddedAt time.Time `json:"addedAt"`
+}
+
+// Recommendation — рекомендованный зрителю фильм
+type Recommendation struct {
+	Movie
+	// Score — вес рекомендации; сравним только между рекомендациями с одним Reason
+	Score float64 `json:"score"`
+	// Reason — источник рекомендации: collaborative, similar или popular
+	Reason string `json:"reason"`
+	// BecauseOf — фильм зрителя, из-за которого рекомендован этот (для similar)
+	BecauseOf string `json:"becauseOf,omitempty"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Действия в журнале правок фильмов
+const (
+	RevisionCreate = "create"
+	RevisionUpdate = "update"
+	RevisionDelete = "delete"
+)
+
+// Откуда пришла правка фильма
+const (
+	// SourceFile — изменение найдено в файле каталога, например правка вручную;
+	// автор в этом случае неизвестен
+	SourceFile = "file"
+	// SourceAPI — возврат к ревизии через API администратора
+	SourceAPI = "api"
+)
+
+// MovieRevision — одна правка фильма в журнале изменений каталога
+type MovieRevision struct {
+	// Revision — сквозной номер правки в журнале начиная с 1
+	Revision int    `json:"revision"`
+	MovieID  string `json:"movieId"`
+	Action   string `json:"action"`
+	// Author — кто внёс правку; пусто, если неизвестно
+	Author string `json:"author,omitempty"`
+	// Source — чем внесена правка: file, api или имя утилиты
+	Source string    `json:"source"`
+	At     time.Time `json:"at"`
+	// RevertOf — номер правки, к состоянию после которой вернули фильм
+	RevertOf int           `json:"revertOf,omitempty"`
+	Changes  []FieldChange `json:"changes"`
+}
+
+// FieldChange — изменение одного поля фильма. Значения хранятся в JSON, как в файле
+// каталога; Old пусто у нового поля, New — у удалённого.
+type FieldChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old,omitempty"`
+	New   json.RawMessage `json:"new,omitempty"`
+}
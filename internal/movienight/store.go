@@ -0,0 +1,464 @@
+// Package movienight проводит голосования компании за фильм на вечер: создатель
+// выбирает фильмы-кандидаты, участники голосуют по ссылке без регистрации,
+// а к сроку голосование завершается само и объявляет победителя
+package movienight
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"movie-catalog/internal/jsonfile"
+	"movie-catalog/internal/models"
+)
+
+// Ограничения голосования
+const (
+	MinMovies = 2
+	MaxMovies = 20
+	// MaxDuration — самый поздний срок голосования от момента создания
+	MaxDuration = 7 * 24 * time.Hour
+	// MaxTitleLength и MaxNameLength — длина названия вечера и имени участника в символах
+	MaxTitleLength = 100
+	MaxNameLength  = 40
+	// retention — сколько хранить завершённые голосования
+	retention = 30 * 24 * time.Hour
+	// purgeInterval — как часто Run удаляет давно завершённые голосования
+	purgeInterval = time.Hour
+)
+
+var (
+	// ErrNotFound — голосования с таким идентификатором нет
+	ErrNotFound = errors.New("голосование не найдено")
+	// ErrClosed — голосование уже завершено
+	ErrClosed = errors.New("голосование завершено")
+	// ErrNotOwner — завершить голосование досрочно может только его создатель
+	ErrNotOwner = errors.New("завершить голосование может только его создатель")
+	// ErrInvalid — голосование или бюллетень не прошли проверку
+	ErrInvalid = errors.New("данные голосования не прошли проверку")
+)
+
+// record — голосование в файле вместе с создателем и бюллетенями по идентификаторам участников
+type record struct {
+	models.MovieNight
+	Owner   string                        `json:"owner"`
+	Ballots map[string]models.NightBallot `json:"ballots,omitempty"`
+}
+
+// errNothingDue — closeDue нечего завершать и удалять, файл не переписывается
+var errNothingDue = errors.New("нет голосований для завершения")
+
+// Store — потокобезопасное хранилище голосований. Голосований немного и живут они
+// не дольше недели, поэтому файл после каждого голоса переписывается целиком.
+type Store struct {
+	nights *jsonfile.Value[map[string]record]
+	// wake будит Run, когда появилось голосование с более ранним сроком
+	wake chan struct{}
+
+	hooksMu sync.Mutex
+	hooks   []func(models.NightResults)
+}
+
+// Open загружает голосования из файла path; если файла нет, голосований пока нет
+func Open(path string) (*Store, error) {
+	nights, err := jsonfile.Open[map[string]record](path, "файл голосований")
+	if err != nil {
+		return nil, err
+	}
+	return &Store{nights: nights, wake: make(chan struct{}, 1)}, nil
+}
+
+// OnChange регистрирует функцию, которая получает итоги после каждого сохранённого
+// голоса и завершения голосования. Она вызывается под блокировкой хранилища, поэтому
+// видит изменения строго в порядке записи и не должна блокироваться или обращаться
+// к хранилищу на запись.
+func (s *Store) OnChange(hook func(models.NightResults)) {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+	s.hooks = append(s.hooks, hook)
+}
+
+// changed передаёт итоги функциям OnChange
+func (s *Store) changed(results models.NightResults) {
+	s.hooksMu.Lock()
+	hooks := s.hooks
+	s.hooksMu.Unlock()
+	for _, hook := range hooks {
+		hook(results)
+	}
+}
+
+// Validate проверяет название, способ голосования, список фильмов и срок
+// относительно момента now. Ошибка оборачивает ErrInvalid.
+func Validate(night models.MovieNight, now time.Time) error {
+	var errs []error
+	if strings.TrimSpace(night.Title) == "" {
+		errs = append(errs, errors.New("пустое название"))
+	} else if utf8.RuneCountInString(night.Title) > MaxTitleLength {
+		errs = append(errs, fmt.Errorf("название длиннее %d символов", MaxTitleLength))
+	}
+	if night.Method != models.VotingApproval && night.Method != models.VotingRanked {
+		errs = append(errs, fmt.Errorf("способ голосования %q: нужен %s или %s", night.Method, models.VotingApproval, models.VotingRanked))
+	}
+	if n := len(night.MovieIDs); n < MinMovies || n > MaxMovies {
+		errs = append(errs, fmt.Errorf("фильмов должно быть от %d до %d", MinMovies, MaxMovies))
+	}
+	seen := make(map[string]bool)
+	for _, id := range night.MovieIDs {
+		if seen[id] {
+			errs = append(errs, fmt.Errorf("фильм %q указан дважды", id))
+		}
+		seen[id] = true
+	}
+	if !night.Deadline.After(now) {
+		errs = append(errs, errors.New("срок голосования уже прошёл"))
+	} else if night.Deadline.Sub(now) > MaxDuration {
+		errs = append(errs, fmt.Errorf("срок голосования дальше %d дней", int(MaxDuration.Hours()/24)))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalid, errors.Join(errs...))
+	}
+	return nil
+}
+
+// validateBallot проверяет, что бюллетень называет только фильмы голосования и без повторов
+func validateBallot(night models.MovieNight, ballot models.NightBallot) error {
+	var errs []error
+	if len(ballot.Choices) == 0 {
+		errs = append(errs, errors.New("не выбрано ни одного фильма"))
+	}
+	allowed := make(map[string]bool, len(night.MovieIDs))
+	for _, id := range night.MovieIDs {
+		allowed[id] = true
+	}
+	seen := make(map[string]bool)
+	for _, id := range ballot.Choices {
+		switch {
+		case !allowed[id]:
+			errs = append(errs, fmt.Errorf("фильма %q нет в голосовании", id))
+		case seen[id]:
+			errs = append(errs, fmt.Errorf("фильм %q указан дважды", id))
+		}
+		seen[id] = true
+	}
+	if utf8.RuneCountInString(ballot.Name) > MaxNameLength {
+		errs = append(errs, fmt.Errorf("имя длиннее %d символов", MaxNameLength))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalid, errors.Join(errs...))
+	}
+	return nil
+}
+
+// Create начинает голосование от имени owner; идентификатор и дата создания
+// проставляются автоматически
+func (s *Store) Create(owner string, night models.MovieNight) (models.MovieNight, error) {
+	now := time.Now().UTC()
+	night.Title = strings.TrimSpace(night.Title)
+	night.Deadline = night.Deadline.UTC()
+	if err := Validate(night, now); err != nil {
+		return models.MovieNight{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return models.MovieNight{}, err
+	}
+	night.ID, night.CreatedAt, night.ClosedAt, night.Winner = id, now, nil, ""
+
+	err = s.nights.Update(func(nights map[string]record) (map[string]record, error) {
+		updated := copyNights(nights)
+		updated[id] = record{MovieNight: night, Owner: owner}
+		return updated, nil
+	}, nil)
+	if err != nil {
+		return models.MovieNight{}, err
+	}
+	s.notify()
+	return night, nil
+}
+
+// Get ищет голосование по идентификатору
+func (s *Store) Get(id string) (models.MovieNight, bool) {
+	var (
+		night models.MovieNight
+		ok    bool
+	)
+	s.nights.Read(func(nights map[string]record) {
+		var r record
+		r, ok = nights[id]
+		night = r.MovieNight
+	})
+	return night, ok
+}
+
+// IsOwner сообщает, создал ли голосование участник voter
+func (s *Store) IsOwner(id, voter string) bool {
+	var owner bool
+	s.nights.Read(func(nights map[string]record) {
+		r, ok := nights[id]
+		owner = ok && voter != "" && r.Owner == voter
+	})
+	return owner
+}
+
+// Results подсчитывает голоса на текущий момент
+func (s *Store) Results(id string) (models.NightResults, bool) {
+	var (
+		results models.NightResults
+		ok      bool
+	)
+	s.Watch(id, func(current models.NightResults, found bool) {
+		results, ok = current, found
+	})
+	return results, ok
+}
+
+// Watch вызывает fn с текущими итогами голосования id; found равен false, если
+// голосования нет. Пока fn работает, итоги не меняются и функции OnChange не
+// вызываются: подписчик, который подключается внутри fn, получит все следующие
+// изменения без пропусков и повторов.
+func (s *Store) Watch(id string, fn func(results models.NightResults, found bool)) {
+	s.nights.Read(func(nights map[string]record) {
+		r, ok := nights[id]
+		if !ok {
+			fn(models.NightResults{}, false)
+			return
+		}
+		fn(r.results(), true)
+	})
+}
+
+// Ballot возвращает бюллетень участника voter
+func (s *Store) Ballot(id, voter string) (models.NightBallot, bool) {
+	var (
+		ballot models.NightBallot
+		ok     bool
+	)
+	s.nights.Read(func(nights map[string]record) {
+		ballot, ok = nights[id].Ballots[voter]
+	})
+	return ballot, ok
+}
+
+// Vote сохраняет или заменяет бюллетень участника voter и возвращает новые итоги
+func (s *Store) Vote(id, voter string, ballot models.NightBallot) (models.NightResults, error) {
+	ballot.Name = strings.TrimSpace(ballot.Name)
+	ballot.UpdatedAt = time.Now().UTC()
+
+	var results models.NightResults
+	err := s.nights.Update(func(nights map[string]record) (map[string]record, error) {
+		r, ok := nights[id]
+		switch {
+		case !ok:
+			return nil, ErrNotFound
+		case r.Closed() || !ballot.UpdatedAt.Before(r.Deadline):
+			return nil, ErrClosed
+		}
+		if err := validateBallot(r.MovieNight, ballot); err != nil {
+			return nil, err
+		}
+
+		ballots := make(map[string]models.NightBallot, len(r.Ballots)+1)
+		for k, v := range r.Ballots {
+			ballots[k] = v
+		}
+		ballots[voter] = ballot
+		r.Ballots = ballots
+
+		updated := copyNights(nights)
+		updated[id] = r
+		results = r.results()
+		return updated, nil
+	}, func(map[string]record) {
+		s.changed(results)
+	})
+	if err != nil {
+		return models.NightResults{}, err
+	}
+	return results, nil
+}
+
+// Close досрочно завершает голосование по просьбе его создателя voter
+func (s *Store) Close(id, voter string) (models.NightResults, error) {
+	var results models.NightResults
+	err := s.nights.Update(func(nights map[string]record) (map[string]record, error) {
+		r, ok := nights[id]
+		switch {
+		case !ok:
+			return nil, ErrNotFound
+		case voter == "" || r.Owner != voter:
+			return nil, ErrNotOwner
+		case r.Closed():
+			return nil, ErrClosed
+		}
+
+		updated := copyNights(nights)
+		results = r.close(time.Now().UTC())
+		updated[id] = r
+		return updated, nil
+	}, func(map[string]record) {
+		s.changed(results)
+	})
+	if err != nil {
+		return models.NightResults{}, err
+	}
+	return results, nil
+}
+
+// Run завершает голосования в срок и вызывает closed с итогами каждого завершённого,
+// пока не отменён ctx. Раз в purgeInterval удаляет голосования, завершённые больше
+// 30 дней назад, даже если новых сроков нет.
+func (s *Store) Run(ctx context.Context, closed func(models.NightResults)) {
+	purge := time.NewTicker(purgeInterval)
+	defer purge.Stop()
+	for {
+		var timer *time.Timer
+		var due <-chan time.Time
+		if deadline, ok := s.nextDeadline(); ok {
+			timer = time.NewTimer(time.Until(deadline))
+			due = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			stopTimer(timer)
+			return
+		case <-s.wake:
+			stopTimer(timer)
+			continue
+		case <-purge.C:
+			stopTimer(timer)
+		case <-due:
+		}
+
+		results, err := s.closeDue(time.Now().UTC())
+		if err != nil {
+			// Файл не записался: повторим через минуту, а не в тесном цикле
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Minute):
+			}
+		}
+		for _, r := range results {
+			closed(r)
+		}
+	}
+}
+
+func stopTimer(t *time.Timer) {
+	if t != nil {
+		t.Stop()
+	}
+}
+
+// nextDeadline возвращает ближайший срок среди идущих голосований
+func (s *Store) nextDeadline() (time.Time, bool) {
+	var next time.Time
+	s.nights.Read(func(nights map[string]record) {
+		for _, r := range nights {
+			if !r.Closed() && (next.IsZero() || r.Deadline.Before(next)) {
+				next = r.Deadline
+			}
+		}
+	})
+	return next, !next.IsZero()
+}
+
+// closeDue завершает голосования со сроком не позже now и удаляет давно завершённые
+func (s *Store) closeDue(now time.Time) ([]models.NightResults, error) {
+	var closed []models.NightResults
+	err := s.nights.Update(func(nights map[string]record) (map[string]record, error) {
+		updated := copyNights(nights)
+		changed := false
+		for id, r := range updated {
+			switch {
+			case !r.Closed() && !r.Deadline.After(now):
+				closed = append(closed, r.close(r.Deadline))
+				updated[id] = r
+				changed = true
+			case r.Closed() && now.Sub(*r.ClosedAt) > retention:
+				delete(updated, id)
+				changed = true
+			}
+		}
+		if !changed {
+			return nil, errNothingDue
+		}
+		sort.Slice(closed, func(i, j int) bool { return closed[i].Night.ID < closed[j].Night.ID })
+		return updated, nil
+	}, func(map[string]record) {
+		for _, results := range closed {
+			s.changed(results)
+		}
+	})
+	if errors.Is(err, errNothingDue) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return closed, nil
+}
+
+// close завершает голосование моментом at и запоминает победителя
+func (r *record) close(at time.Time) models.NightResults {
+	r.ClosedAt = &at
+	results := r.results()
+	r.Winner = results.Leader
+	results.Night = r.MovieNight
+	return results
+}
+
+// results подсчитывает голоса; бюллетени упорядочены по времени, чтобы имена
+// участников шли в порядке голосования
+func (r record) results() models.NightResults {
+	voters := make([]string, 0, len(r.Ballots))
+	for voter := range r.Ballots {
+		voters = append(voters, voter)
+	}
+	sort.Slice(voters, func(i, j int) bool {
+		a, b := r.Ballots[voters[i]], r.Ballots[voters[j]]
+		if !a.UpdatedAt.Equal(b.UpdatedAt) {
+			return a.UpdatedAt.Before(b.UpdatedAt)
+		}
+		return voters[i] < voters[j]
+	})
+	ballots := make([]models.NightBallot, len(voters))
+	for i, voter := range voters {
+		ballots[i] = r.Ballots[voter]
+	}
+	return Tally(r.MovieNight, ballots)
+}
+
+// copyNights возвращает копию списка голосований для изменения
+func copyNights(nights map[string]record) map[string]record {
+	updated := make(map[string]record, len(nights)+1)
+	for k, v := range nights {
+		updated[k] = v
+	}
+	return updated
+}
+
+// notify будит Run, не блокируясь
+func (s *Store) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// newID возвращает случайный идентификатор голосования для ссылки
+func newID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
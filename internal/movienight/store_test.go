@@ -0,0 +1,127 @@
+package movienight
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"movie-catalog/internal/models"
+)
+
+func openStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "nights.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func createNight(t *testing.T, store *Store, owner string, deadline time.Time) models.MovieNight {
+	t.Helper()
+	night, err := store.Create(owner, models.MovieNight{
+		Title:    "Пятница",
+		Method:   models.VotingApproval,
+		MovieIDs: []string{"inception", "arrival", "dune"},
+		Deadline: deadline,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return night
+}
+
+func TestVoteAfterDeadline(t *testing.T) {
+	store := openStore(t)
+	night := createNight(t, store, "owner", time.Now().Add(50*time.Millisecond))
+
+	if _, err := store.Vote(night.ID, "alice", models.NightBallot{Choices: []string{"arrival"}}); err != nil {
+		t.Fatalf("голос до срока: %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	// Run ещё не успел завершить голосование, но голос после срока не принимается
+	if _, err := store.Vote(night.ID, "bob", models.NightBallot{Choices: []string{"dune"}}); !errors.Is(err, ErrClosed) {
+		t.Fatalf("голос после срока: ошибка %v, ожидалась ErrClosed", err)
+	}
+	if results, _ := store.Results(night.ID); results.Voters != 1 {
+		t.Errorf("участников %d, ожидался 1", results.Voters)
+	}
+	if _, err := store.Vote("missing", "bob", models.NightBallot{Choices: []string{"dune"}}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("голос в несуществующем: ошибка %v, ожидалась ErrNotFound", err)
+	}
+}
+
+func TestCloseByNonOwner(t *testing.T) {
+	store := openStore(t)
+	night := createNight(t, store, "owner", time.Now().Add(time.Hour))
+
+	for _, voter := range []string{"alice", ""} {
+		if _, err := store.Close(night.ID, voter); !errors.Is(err, ErrNotOwner) {
+			t.Errorf("завершение участником %q: ошибка %v, ожидалась ErrNotOwner", voter, err)
+		}
+	}
+	if got, _ := store.Get(night.ID); got.Closed() {
+		t.Fatal("голосование завершено не создателем")
+	}
+
+	results, err := store.Close(night.ID, "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results.Night.Closed() {
+		t.Error("голосование не завершено создателем")
+	}
+	if _, err := store.Close(night.ID, "owner"); !errors.Is(err, ErrClosed) {
+		t.Errorf("повторное завершение: ошибка %v, ожидалась ErrClosed", err)
+	}
+}
+
+func TestCloseDueAtDeadline(t *testing.T) {
+	store := openStore(t)
+	night := createNight(t, store, "owner", time.Now().Add(time.Hour))
+	other := createNight(t, store, "owner", time.Now().Add(2*time.Hour))
+	for voter, choice := range map[string]string{"alice": "arrival", "bob": "arrival", "carol": "dune"} {
+		if _, err := store.Vote(night.ID, voter, models.NightBallot{Choices: []string{choice}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var published []models.NightResults
+	store.OnChange(func(results models.NightResults) { published = append(published, results) })
+
+	// До срока завершать нечего
+	if closed, err := store.closeDue(night.Deadline.Add(-time.Second)); err != nil || len(closed) != 0 {
+		t.Fatalf("до срока: %v, %v", closed, err)
+	}
+
+	closed, err := store.closeDue(night.Deadline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(closed) != 1 || closed[0].Night.ID != night.ID {
+		t.Fatalf("завершены %+v, ожидалось только первое голосование", closed)
+	}
+	got := closed[0].Night
+	if got.ClosedAt == nil || !got.ClosedAt.Equal(night.Deadline) {
+		t.Errorf("время завершения %v, ожидался срок %v", got.ClosedAt, night.Deadline)
+	}
+	if got.Winner != "arrival" {
+		t.Errorf("победитель %q, ожидался arrival", got.Winner)
+	}
+	if len(published) != 1 || !published[0].Night.Closed() {
+		t.Errorf("опубликовано %+v, ожидались итоги завершённого голосования", published)
+	}
+	if next, ok := store.nextDeadline(); !ok || !next.Equal(other.Deadline) {
+		t.Errorf("следующий срок %v, ожидался %v", next, other.Deadline)
+	}
+
+	// Давно завершённые голосования удаляются
+	if _, err := store.closeDue(night.Deadline.Add(retention + time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Get(night.ID); ok {
+		t.Error("голосование не удалено после срока хранения")
+	}
+}
@@ -0,0 +1,114 @@
+package movienight
+
+import (
+	"sort"
+
+	"movie-catalog/internal/models"
+)
+
+// Tally подсчитывает голоса. При равенстве выше стоит фильм, который создатель
+// поставил в списке раньше.
+func Tally(night models.MovieNight, ballots []models.NightBallot) models.NightResults {
+	results := models.NightResults{Night: night, Voters: len(ballots), Participants: []string{}}
+	for _, ballot := range ballots {
+		if ballot.Name != "" {
+			results.Participants = append(results.Participants, ballot.Name)
+		}
+	}
+
+	if night.Method == models.VotingRanked {
+		results.Rounds = instantRunoff(night.MovieIDs, ballots)
+		if len(results.Rounds) > 0 {
+			results.Tally = results.Rounds[0].Tally
+			last := results.Rounds[len(results.Rounds)-1]
+			if len(last.Tally) > 0 && last.Tally[0].Votes > 0 {
+				results.Leader = last.Tally[0].MovieID
+			}
+			return results
+		}
+		results.Tally = rank(night.MovieIDs, nil)
+	} else {
+		votes := make(map[string]int)
+		for _, ballot := range ballots {
+			for _, movieID := range ballot.Choices {
+				votes[movieID]++
+			}
+		}
+		results.Tally = rank(night.MovieIDs, votes)
+	}
+	if len(results.Tally) > 0 && results.Tally[0].Votes > 0 {
+		results.Leader = results.Tally[0].MovieID
+	}
+	return results
+}
+
+// instantRunoff проводит туры: в каждом голос бюллетеня отдаётся первому из оставшихся
+// фильмов. Побеждает фильм, набравший больше половины голосов; иначе выбывает фильм
+// с наименьшим числом голосов, а фильмы без голосов выбывают все сразу.
+func instantRunoff(movieIDs []string, ballots []models.NightBallot) []models.NightRound {
+	if len(ballots) == 0 {
+		return nil
+	}
+	remaining := append([]string(nil), movieIDs...)
+	var rounds []models.NightRound
+	for len(remaining) > 0 {
+		votes := make(map[string]int)
+		active := 0
+		for _, ballot := range ballots {
+			if movieID, ok := firstRemaining(ballot.Choices, remaining); ok {
+				votes[movieID]++
+				active++
+			}
+		}
+		round := models.NightRound{Tally: rank(remaining, votes)}
+		if active == 0 || round.Tally[0].Votes*2 > active || len(remaining) == 1 {
+			rounds = append(rounds, round)
+			break
+		}
+
+		last := round.Tally[len(round.Tally)-1]
+		eliminated := map[string]bool{last.MovieID: true}
+		if last.Votes == 0 {
+			for _, t := range round.Tally {
+				if t.Votes == 0 {
+					eliminated[t.MovieID] = true
+				}
+			}
+		}
+		var next []string
+		for _, movieID := range remaining {
+			if eliminated[movieID] {
+				round.Eliminated = append(round.Eliminated, movieID)
+			} else {
+				next = append(next, movieID)
+			}
+		}
+		rounds = append(rounds, round)
+		remaining = next
+	}
+	return rounds
+}
+
+// firstRemaining возвращает первый выбор бюллетеня из ещё не выбывших фильмов
+func firstRemaining(choices, remaining []string) (string, bool) {
+	for _, choice := range choices {
+		for _, movieID := range remaining {
+			if choice == movieID {
+				return choice, true
+			}
+		}
+	}
+	return "", false
+}
+
+// rank возвращает голоса за фильмы movieIDs по убыванию с сохранением исходного порядка при равенстве
+func rank(movieIDs []string, votes map[string]int) []models.NightTally {
+	tally := make([]models.NightTally, len(movieIDs))
+	for i, movieID := range movieIDs {
+		tally[i] = models.NightTally{MovieID: movieID, Votes: votes[movieID]}
+	}
+	sort.SliceStable(tally, func(i, j int) bool {
+		return tally[i].Votes > tally[j].Votes
+	})
+	return tally
+}
@@ -0,0 +1,71 @@
+package movienight
+
+import (
+	"testing"
+
+	"movie-catalog/internal/models"
+)
+
+func ballots(choices ...[]string) []models.NightBallot {
+	list := make([]models.NightBallot, len(choices))
+	for i, c := range choices {
+		list[i] = models.NightBallot{Choices: c}
+	}
+	return list
+}
+
+func TestTallyApproval(t *testing.T) {
+	night := models.MovieNight{Method: models.VotingApproval, MovieIDs: []string{"tenet", "moon", "dune"}}
+	got := Tally(night, ballots(
+		[]string{"moon", "dune"},
+		[]string{"dune"},
+		[]string{"tenet", "moon"},
+	))
+	// moon и dune набрали по два голоса, moon раньше в списке
+	if got.Leader != "moon" || got.Voters != 3 {
+		t.Errorf("Leader = %q, Voters = %d, want moon and 3", got.Leader, got.Voters)
+	}
+	want := []models.NightTally{{MovieID: "moon", Votes: 2}, {MovieID: "dune", Votes: 2}, {MovieID: "tenet", Votes: 1}}
+	for i, w := range want {
+		if got.Tally[i] != w {
+			t.Errorf("Tally[%d] = %+v, want %+v", i, got.Tally[i], w)
+		}
+	}
+}
+
+func TestTallyRankedRunoff(t *testing.T) {
+	night := models.MovieNight{Method: models.VotingRanked, MovieIDs: []string{"tenet", "moon", "dune", "cats"}}
+	// В первом туре tenet и moon набрали по два голоса из пяти, но голос выбывшего dune переходит к moon
+	got := Tally(night, ballots(
+		[]string{"tenet", "moon"},
+		[]string{"tenet"},
+		[]string{"moon", "dune"},
+		[]string{"moon"},
+		[]string{"dune", "moon"},
+	))
+	if got.Tally[0].MovieID != "tenet" || got.Tally[0].Votes != 2 {
+		t.Errorf("first round leader = %+v, want tenet with 2 votes", got.Tally[0])
+	}
+	if got.Leader != "moon" {
+		t.Errorf("Leader = %q, want moon", got.Leader)
+	}
+	// Сначала выбывает фильм без голосов, затем dune с одним голосом
+	if len(got.Rounds) != 3 {
+		t.Fatalf("Rounds = %+v, want 3 rounds", got.Rounds)
+	}
+	for i, want := range []string{"cats", "dune"} {
+		if e := got.Rounds[i].Eliminated; len(e) != 1 || e[0] != want {
+			t.Errorf("Rounds[%d].Eliminated = %q, want [%s]", i, e, want)
+		}
+	}
+}
+
+func TestTallyWithoutBallots(t *testing.T) {
+	for _, method := range []string{models.VotingApproval, models.VotingRanked} {
+		night := models.MovieNight{Method: method, MovieIDs: []string{"tenet", "moon"}}
+		got := Tally(night, nil)
+		if got.Leader != "" || len(got.Tally) != 2 || got.Participants == nil {
+			t.Errorf("%s: Tally without ballots = %+v", method, got)
+		}
+	}
+}
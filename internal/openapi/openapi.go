@@ -0,0 +1,145 @@
+// Package openapi описывает API каталога в формате OpenAPI 3
+package openapi
+
+// Version — версия спецификации OpenAPI
+const Version = "3.0.3"
+
+// Document — корневой объект спецификации
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info — сведения об API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem — операции пути по HTTP-методам в нижнем регистре ("get", "post", ...)
+type PathItem map[string]Operation
+
+// Operation — описание одной операции
+type Operation struct {
+	Summary     string              `json:"summary"`
+	Description string              `json:"description,omitempty"`
+	OperationID string              `json:"operationId"`
+	Tags        []string            `json:"tags,omitempty"`
+	Deprecated  bool                `json:"deprecated,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+	// Security — требуемые схемы авторизации по именам из components.securitySchemes
+	Security []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter — параметр пути, запроса или заголовка
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody — тело запроса
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response — вариант ответа
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header — заголовок ответа
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// MediaType — схема содержимого для конкретного типа
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components — переиспользуемые схемы
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme — способ авторизации, например HTTP Bearer
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Schema — подмножество JSON Schema, используемое в OpenAPI 3.0
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+}
+
+// Ref возвращает ссылку на схему из components
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// ArrayOf возвращает схему массива элементов items
+func ArrayOf(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// MapOf возвращает схему объекта с произвольными ключами и значениями values
+func MapOf(values *Schema) *Schema {
+	return &Schema{Type: "object", AdditionalProperties: values}
+}
+
+// String возвращает схему строки
+func String() *Schema {
+	return &Schema{Type: "string"}
+}
+
+// Integer возвращает схему целого числа
+func Integer() *Schema {
+	return &Schema{Type: "integer"}
+}
+
+// JSONBody возвращает обязательное JSON-тело запроса по схеме schema
+func JSONBody(description string, schema *Schema) *RequestBody {
+	return &RequestBody{
+		Description: description,
+		Required:    true,
+		Content:     map[string]MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+// JSON возвращает ответ с JSON-содержимым по схеме schema
+func JSON(description string, schema *Schema) Response {
+	return Response{
+		Description: description,
+		Content:     map[string]MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+// PathParam возвращает обязательный строковый параметр пути
+func PathParam(name, description string) Parameter {
+	return Parameter{Name: name, In: "path", Description: description, Required: true, Schema: String()}
+}
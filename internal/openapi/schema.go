@@ -0,0 +1,138 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Registry собирает схемы components по типам Go, чтобы описание API
+// не расходилось с моделями: свойства берутся из json-тегов полей
+type Registry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// NewRegistry создаёт пустой реестр схем
+func NewRegistry() *Registry {
+	return &Registry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// Register добавляет схему структуры value под именем name и возвращает ссылку на неё.
+// Поля с тегом omitempty считаются необязательными, поля с тегом "-" пропускаются.
+func (r *Registry) Register(name string, value interface{}) *Schema {
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	r.names[t] = name
+	r.schemas[name] = r.structSchema(t)
+	return Ref(name)
+}
+
+// Schemas возвращает зарегистрированные схемы
+func (r *Registry) Schemas() map[string]*Schema {
+	return r.schemas
+}
+
+// Describe задаёт описание свойства зарегистрированной схемы
+func (r *Registry) Describe(name, property, description string) {
+	if schema, ok := r.schemas[name]; ok {
+		if prop, ok := schema.Properties[property]; ok {
+			prop.Description = description
+		}
+	}
+}
+
+// Enum ограничивает значения свойства зарегистрированной схемы
+func (r *Registry) Enum(name, property string, values []string) {
+	if schema, ok := r.schemas[name]; ok {
+		if prop, ok := schema.Properties[property]; ok {
+			prop.Enum = values
+		}
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// rawJSONType — значение в JSON как есть: схема без ограничений типа
+var rawJSONType = reflect.TypeOf(json.RawMessage(nil))
+
+func (r *Registry) schemaOf(t reflect.Type) *Schema {
+	if name, ok := r.names[t]; ok {
+		return Ref(name)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := r.schemaOf(t.Elem())
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t == rawJSONType {
+			return &Schema{}
+		}
+		return ArrayOf(r.schemaOf(t.Elem()))
+	case reflect.Map:
+		return MapOf(r.schemaOf(t.Elem()))
+	case reflect.Struct:
+		if t == timeType {
+			return &Schema{Type: "string", Format: "date-time"}
+		}
+		return r.structSchema(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func (r *Registry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && tag == "" {
+			embedded := r.structSchema(field.Type)
+			for propName, prop := range embedded.Properties {
+				schema.Properties[propName] = prop
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+
+		schema.Properties[name] = r.schemaOf(field.Type)
+		if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
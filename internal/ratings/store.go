@@ -0,0 +1,285 @@
+// Package ratings хранит оценки фильмов и списки «посмотреть позже» анонимных зрителей
+// в JSON-файле
+package ratings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"movie-catalog/internal/jsonfile"
+	"movie-catalog/internal/models"
+)
+
+// ErrInvalidRating — оценка вне диапазона от models.MinRating до models.MaxRating
+var ErrInvalidRating = fmt.Errorf("оценка должна быть от %d до %d", models.MinRating, models.MaxRating)
+
+// errNoUser — пустой идентификатор зрителя
+var errNoUser = errors.New("не указан зритель")
+
+// user — данные одного зрителя в файле
+type user struct {
+	Ratings   map[string]rating    `json:"ratings,omitempty"`
+	Watchlist map[string]time.Time `json:"watchlist,omitempty"`
+}
+
+type rating struct {
+	Rating    int       `json:"rating"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// flushDelay — сколько копить изменения оценок перед записью файла
+const flushDelay = time.Second
+
+// Store — потокобезопасное хранилище оценок и списков зрителей по их идентификаторам.
+// Оценки ставят все посетители, поэтому изменения применяются в памяти, а файл
+// переписывается в фоне (см. Run) не чаще раза в flushDelay.
+type Store struct {
+	path string
+	// dirty будит Run после изменения
+	dirty chan struct{}
+	// flushMu не даёт двум записям файла перегнать друг друга
+	flushMu sync.Mutex
+
+	mu      sync.RWMutex
+	users   map[string]user
+	version uint64
+	// saved — версия данных, записанная в файл
+	saved uint64
+}
+
+// Open загружает оценки из файла path; если файла нет, оценок пока нет
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, dirty: make(chan struct{}, 1), users: make(map[string]user)}
+	if _, err := jsonfile.Read(path, &s.users); err != nil {
+		return nil, fmt.Errorf("не удалось прочитать файл оценок: %w", err)
+	}
+	if s.users == nil {
+		s.users = make(map[string]user)
+	}
+	return s, nil
+}
+
+// Version возвращает номер версии данных; он растёт с каждым изменением оценок
+func (s *Store) Version() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
+// Rate ставит или меняет оценку фильма зрителем
+func (s *Store) Rate(userID, movieID string, value int) (models.UserRating, error) {
+	if userID == "" {
+		return models.UserRating{}, errNoUser
+	}
+	if value < models.MinRating || value > models.MaxRating {
+		return models.UserRating{}, ErrInvalidRating
+	}
+	r := rating{Rating: value, UpdatedAt: time.Now().UTC()}
+	err := s.update(userID, func(u *user) bool {
+		if u.Ratings == nil {
+			u.Ratings = make(map[string]rating)
+		}
+		u.Ratings[movieID] = r
+		return true
+	})
+	if err != nil {
+		return models.UserRating{}, err
+	}
+	return models.UserRating{MovieID: movieID, Rating: r.Rating, UpdatedAt: r.UpdatedAt}, nil
+}
+
+// Unrate убирает оценку фильма; отсутствие оценки ошибкой не считается
+func (s *Store) Unrate(userID, movieID string) error {
+	return s.update(userID, func(u *user) bool {
+		if _, ok := u.Ratings[movieID]; !ok {
+			return false
+		}
+		delete(u.Ratings, movieID)
+		return true
+	})
+}
+
+// AddToWatchlist откладывает фильм на потом; повторное добавление не меняет дату
+func (s *Store) AddToWatchlist(userID, movieID string) error {
+	if userID == "" {
+		return errNoUser
+	}
+	return s.update(userID, func(u *user) bool {
+		if _, ok := u.Watchlist[movieID]; ok {
+			return false
+		}
+		if u.Watchlist == nil {
+			u.Watchlist = make(map[string]time.Time)
+		}
+		u.Watchlist[movieID] = time.Now().UTC()
+		return true
+	})
+}
+
+// RemoveFromWatchlist убирает фильм из списка «посмотреть позже»
+func (s *Store) RemoveFromWatchlist(userID, movieID string) error {
+	return s.update(userID, func(u *user) bool {
+		if _, ok := u.Watchlist[movieID]; !ok {
+			return false
+		}
+		delete(u.Watchlist, movieID)
+		return true
+	})
+}
+
+// Ratings возвращает оценки зрителя, последние изменённые первыми
+func (s *Store) Ratings(userID string) []models.UserRating {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]models.UserRating, 0, len(s.users[userID].Ratings))
+	for movieID, r := range s.users[userID].Ratings {
+		list = append(list, models.UserRating{MovieID: movieID, Rating: r.Rating, UpdatedAt: r.UpdatedAt})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].UpdatedAt.Equal(list[j].UpdatedAt) {
+			return list[i].UpdatedAt.After(list[j].UpdatedAt)
+		}
+		return list[i].MovieID < list[j].MovieID
+	})
+	return list
+}
+
+// Rating возвращает оценку фильма зрителем; false — оценки нет
+func (s *Store) Rating(userID, movieID string) (int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.users[userID].Ratings[movieID]
+	return r.Rating, ok
+}
+
+// Watchlist возвращает отложенные зрителем фильмы, последние добавленные первыми
+func (s *Store) Watchlist(userID string) []models.WatchlistItem {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]models.WatchlistItem, 0, len(s.users[userID].Watchlist))
+	for movieID, addedAt := range s.users[userID].Watchlist {
+		list = append(list, models.WatchlistItem{MovieID: movieID, AddedAt: addedAt})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].AddedAt.Equal(list[j].AddedAt) {
+			return list[i].AddedAt.After(list[j].AddedAt)
+		}
+		return list[i].MovieID < list[j].MovieID
+	})
+	return list
+}
+
+// InWatchlist сообщает, отложен ли фильм зрителем
+func (s *Store) InWatchlist(userID, movieID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.users[userID].Watchlist[movieID]
+	return ok
+}
+
+// Matrix возвращает копию всех оценок: зритель → фильм → оценка
+func (s *Store) Matrix() map[string]map[string]float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matrix := make(map[string]map[string]float64, len(s.users))
+	for userID, u := range s.users {
+		if len(u.Ratings) == 0 {
+			continue
+		}
+		row := make(map[string]float64, len(u.Ratings))
+		for movieID, r := range u.Ratings {
+			row[movieID] = float64(r.Rating)
+		}
+		matrix[userID] = row
+	}
+	return matrix
+}
+
+// update изменяет данные зрителя функцией change и, если она сообщила об изменении,
+// отмечает, что файл нужно переписать. Пустые записи зрителей удаляются.
+func (s *Store) update(userID string, change func(u *user) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.users[userID]
+	if !change(&u) {
+		return nil
+	}
+	if len(u.Ratings) == 0 && len(u.Watchlist) == 0 {
+		delete(s.users, userID)
+	} else {
+		s.users[userID] = u
+	}
+	s.version++
+
+	select {
+	case s.dirty <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run записывает изменения в файл через flushDelay после первого из них, пока
+// не отменён ctx. При ошибке записи изменения остаются в памяти и записываются
+// со следующей попыткой. Перед остановкой сервера вызовите Flush.
+func (s *Store) Run(ctx context.Context, log *slog.Logger) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.dirty:
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(flushDelay):
+		}
+		if err := s.Flush(); err != nil {
+			log.Error("Ошибка при сохранении оценок", "path", s.path, "error", err)
+			select {
+			case s.dirty <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Flush записывает в файл изменения, которые ещё не сохранены
+func (s *Store) Flush() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.RLock()
+	version := s.version
+	if version == s.saved {
+		s.mu.RUnlock()
+		return nil
+	}
+	// Снимок кодируется под блокировкой, а файл пишется уже без неё
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(s.users)
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить оценки: %w", err)
+	}
+
+	if err := jsonfile.Write(s.path, json.RawMessage(bytes.TrimSpace(buf.Bytes()))); err != nil {
+		return fmt.Errorf("не удалось сохранить оценки: %w", err)
+	}
+	s.mu.Lock()
+	s.saved = version
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,54 @@
+package ratings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFlushPersistsChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratings.json")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Rate("alice", "inception", 9); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddToWatchlist("alice", "arrival"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Rate("bob", "dune", 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Unrate("bob", "dune"); err != nil {
+		t.Fatal(err)
+	}
+	if store.Version() != 4 {
+		t.Errorf("версия %d, ожидалась 4", store.Version())
+	}
+
+	// До Flush изменения только в памяти
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("файл записан до Flush: %v", err)
+	}
+	if err := store.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rating, ok := reopened.Rating("alice", "inception"); !ok || rating != 9 {
+		t.Errorf("оценка после перезапуска %d, %v", rating, ok)
+	}
+	if !reopened.InWatchlist("alice", "arrival") {
+		t.Error("отложенный фильм потерян")
+	}
+	// Зритель без оценок и списка не хранится
+	if matrix := reopened.Matrix(); len(matrix) != 1 {
+		t.Errorf("зрителей с оценками %d, ожидался 1", len(matrix))
+	}
+}
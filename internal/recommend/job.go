@@ -0,0 +1,81 @@
+package recommend
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Source отдаёт оценки для построения модели и номер их версии
+type Source interface {
+	Matrix() map[string]map[string]float64
+	Version() uint64
+}
+
+// Job периодически перестраивает модель в фоне. Перестройка пропускается,
+// если оценки не менялись, а загрузка процессора ограничена долей CPUShare одного ядра.
+type Job struct {
+	source   Source
+	interval time.Duration
+	cpuShare float64
+	log      *slog.Logger
+	// sleep делает паузы ограничителя; в тестах подменяется
+	sleep func(ctx context.Context, d time.Duration) error
+
+	model atomic.Pointer[Model]
+	// built и builtVersion используются только горутиной Run
+	built        bool
+	builtVersion uint64
+}
+
+// NewJob создаёт задачу с пустой моделью; модель строится вызовом Run
+func NewJob(source Source, interval time.Duration, cpuShare float64, log *slog.Logger) *Job {
+	j := &Job{source: source, interval: interval, cpuShare: cpuShare, log: log, sleep: sleepContext}
+	j.model.Store(&Model{})
+	return j
+}
+
+// Model возвращает последнюю построенную модель
+func (j *Job) Model() *Model {
+	return j.model.Load()
+}
+
+// Run строит модель сразу и затем раз в interval, пока не отменён ctx
+func (j *Job) Run(ctx context.Context) {
+	j.rebuild(ctx)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.rebuild(ctx)
+		}
+	}
+}
+
+// rebuild перестраивает модель, если оценки изменились с прошлого построения
+func (j *Job) rebuild(ctx context.Context) {
+	version := j.source.Version()
+	if j.built && version == j.builtVersion {
+		return
+	}
+
+	started := time.Now()
+	matrix := j.source.Matrix()
+	throttle := NewThrottle(j.cpuShare)
+	throttle.sleep = j.sleep
+	model, err := Build(ctx, matrix, throttle)
+	if err != nil {
+		if ctx.Err() == nil {
+			j.log.Error("Ошибка построения модели рекомендаций", "error", err)
+		}
+		return
+	}
+	j.model.Store(model)
+	j.built, j.builtVersion = true, version
+	j.log.Info("Модель рекомендаций перестроена", "users", len(matrix), "movies", len(model.neighbors), "duration", time.Since(started))
+}
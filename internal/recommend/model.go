@@ -0,0 +1,307 @@
+// Package recommend строит персональные рекомендации по оценкам зрителей:
+// коллаборативная фильтрация «фильм — фильм» с откатом на похожие по содержанию
+// и популярные фильмы, пока оценок мало
+package recommend
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"movie-catalog/internal/models"
+)
+
+// Параметры модели
+const (
+	// neighborsPerMovie — сколько самых похожих фильмов хранится для каждого
+	neighborsPerMovie = 20
+	// minCoRaters — сколько зрителей должны оценить оба фильма, чтобы их сходству можно было верить
+	minCoRaters = 2
+	// minRatings — сколько оценок нужно зрителю для коллаборативных рекомендаций
+	minRatings = 3
+	// popularityPrior — вес средней оценки по всем фильмам в байесовском среднем:
+	// фильм с одной десяткой не обгоняет фильм с двадцатью девятками
+	popularityPrior = 5
+	// likedRating — оценка, начиная с которой фильм считается понравившимся
+	likedRating = 7
+)
+
+// Neighbor — похожий фильм с коэффициентом сходства по оценкам зрителей
+type Neighbor struct {
+	MovieID    string
+	Similarity float64
+}
+
+// Model — сходство фильмов по оценкам зрителей и популярность фильмов.
+// Неизменяема после построения; нулевое значение — пустая модель.
+type Model struct {
+	neighbors map[string][]Neighbor
+	popular   []string
+	// scores — байесовское среднее оценки фильма; meanRating — средняя по всем оценкам
+	scores     map[string]float64
+	meanRating float64
+}
+
+// Build строит модель по оценкам: зритель → фильм → оценка. Сходство фильмов —
+// скорректированный косинус: оценки центрируются по среднему зрителя, чтобы строгие
+// и щедрые зрители были сравнимы. throttle ограничивает загрузку процессора;
+// отмена ctx прерывает построение.
+func Build(ctx context.Context, matrix map[string]map[string]float64, throttle *Throttle) (*Model, error) {
+	type pair struct{ a, b string }
+	dot := make(map[pair]float64)
+	coRaters := make(map[pair]int)
+	norms := make(map[string]float64)
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	var total float64
+	var totalCount int
+
+	for _, ratings := range sortedRows(matrix) {
+		if err := throttle.Step(ctx); err != nil {
+			return nil, err
+		}
+		mean := meanOf(ratings)
+		items := make([]string, 0, len(ratings))
+		for movieID, value := range ratings {
+			items = append(items, movieID)
+			sums[movieID] += value
+			counts[movieID]++
+			total += value
+			totalCount++
+			centered := value - mean
+			norms[movieID] += centered * centered
+		}
+		sort.Strings(items)
+		for i, a := range items {
+			// У зрителя с тысячами оценок пар миллионы: паузы нужны и внутри строки
+			if err := throttle.Step(ctx); err != nil {
+				return nil, err
+			}
+			for _, b := range items[i+1:] {
+				p := pair{a, b}
+				dot[p] += (ratings[a] - mean) * (ratings[b] - mean)
+				coRaters[p]++
+			}
+		}
+	}
+
+	m := &Model{neighbors: make(map[string][]Neighbor), scores: make(map[string]float64)}
+	for p, value := range dot {
+		if err := throttle.Step(ctx); err != nil {
+			return nil, err
+		}
+		if coRaters[p] < minCoRaters || norms[p.a] == 0 || norms[p.b] == 0 {
+			continue
+		}
+		similarity := value / math.Sqrt(norms[p.a]*norms[p.b])
+		if similarity <= 0 {
+			continue
+		}
+		m.neighbors[p.a] = append(m.neighbors[p.a], Neighbor{MovieID: p.b, Similarity: similarity})
+		m.neighbors[p.b] = append(m.neighbors[p.b], Neighbor{MovieID: p.a, Similarity: similarity})
+	}
+	for movieID, list := range m.neighbors {
+		if err := throttle.Step(ctx); err != nil {
+			return nil, err
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Similarity != list[j].Similarity {
+				return list[i].Similarity > list[j].Similarity
+			}
+			return list[i].MovieID < list[j].MovieID
+		})
+		if len(list) > neighborsPerMovie {
+			list = list[:neighborsPerMovie]
+		}
+		m.neighbors[movieID] = list
+	}
+
+	// Популярность — байесовское среднее оценки фильма
+	if totalCount > 0 {
+		m.meanRating = total / float64(totalCount)
+		for movieID := range counts {
+			m.scores[movieID] = (popularityPrior*m.meanRating + sums[movieID]) / float64(popularityPrior+counts[movieID])
+			m.popular = append(m.popular, movieID)
+		}
+		sort.Slice(m.popular, func(i, j int) bool {
+			a, b := m.scores[m.popular[i]], m.scores[m.popular[j]]
+			if a != b {
+				return a > b
+			}
+			return m.popular[i] < m.popular[j]
+		})
+	}
+	return m, nil
+}
+
+// Neighbors возвращает фильмы, похожие на movieID по оценкам зрителей
+func (m *Model) Neighbors(movieID string) []Neighbor {
+	return m.neighbors[movieID]
+}
+
+// Rating возвращает сглаженную среднюю оценку фильма от MinRating до MaxRating.
+// У фильмов без оценок это средняя по всем оценкам, а пока оценок нет совсем —
+// середина шкалы.
+func (m *Model) Rating(movieID string) float64 {
+	if score, ok := m.scores[movieID]; ok {
+		return score
+	}
+	if m.meanRating > 0 {
+		return m.meanRating
+	}
+	return float64(models.MinRating+models.MaxRating) / 2
+}
+
+// Profile — что известно о зрителе
+type Profile struct {
+	// Ratings — оценки зрителя: фильм → оценка
+	Ratings map[string]float64
+	// Watchlist — отложенные фильмы, последние добавленные первыми
+	Watchlist []string
+}
+
+// Suggestion — рекомендованный фильм
+type Suggestion struct {
+	MovieID   string
+	Score     float64
+	Reason    string
+	BecauseOf string
+}
+
+// SimilarFunc возвращает фильмы, похожие на movieID по содержанию, от самых похожих
+type SimilarFunc func(movieID string, limit int) []Suggestion
+
+// Recommend подбирает зрителю не больше limit фильмов, которых он ещё не оценил и не отложил.
+// Сначала идут фильмы по оценкам похожих зрителей (если оценок у зрителя достаточно),
+// затем похожие по содержанию на понравившиеся и отложенные, затем популярные.
+// available отсеивает фильмы, которых уже нет в каталоге.
+func (m *Model) Recommend(profile Profile, limit int, similar SimilarFunc, available func(movieID string) bool) []Suggestion {
+	seen := make(map[string]bool)
+	for movieID := range profile.Ratings {
+		seen[movieID] = true
+	}
+	for _, movieID := range profile.Watchlist {
+		seen[movieID] = true
+	}
+
+	var result []Suggestion
+	add := func(candidates []Suggestion) {
+		for _, s := range candidates {
+			if len(result) >= limit {
+				return
+			}
+			if !seen[s.MovieID] && available(s.MovieID) {
+				seen[s.MovieID] = true
+				result = append(result, s)
+			}
+		}
+	}
+
+	if len(profile.Ratings) >= minRatings {
+		add(m.collaborative(profile.Ratings))
+	}
+	if similar != nil {
+		add(contentBased(profile, similar, limit))
+	}
+	popular := make([]Suggestion, len(m.popular))
+	for i, movieID := range m.popular {
+		popular[i] = Suggestion{MovieID: movieID, Score: float64(len(m.popular) - i), Reason: models.ReasonPopular}
+	}
+	add(popular)
+	return result
+}
+
+// collaborative предсказывает отклонение оценки зрителя от его средней по оценкам
+// похожих фильмов и возвращает фильмы с положительным предсказанием
+func (m *Model) collaborative(ratings map[string]float64) []Suggestion {
+	mean := meanOf(ratings)
+	num := make(map[string]float64)
+	den := make(map[string]float64)
+	for movieID, value := range ratings {
+		for _, n := range m.neighbors[movieID] {
+			if _, rated := ratings[n.MovieID]; rated {
+				continue
+			}
+			num[n.MovieID] += n.Similarity * (value - mean)
+			den[n.MovieID] += n.Similarity
+		}
+	}
+
+	var result []Suggestion
+	for movieID, d := range den {
+		if predicted := num[movieID] / d; predicted > 0 {
+			result = append(result, Suggestion{MovieID: movieID, Score: predicted, Reason: models.ReasonCollaborative})
+		}
+	}
+	sortSuggestions(result)
+	return result
+}
+
+// contentBased собирает фильмы, похожие по содержанию на понравившиеся зрителю
+// (оценка не ниже likedRating) и отложенные им; у каждого фильма остаётся лучшее сходство
+func contentBased(profile Profile, similar SimilarFunc, limit int) []Suggestion {
+	var seeds []string
+	for movieID, value := range profile.Ratings {
+		if value >= likedRating {
+			seeds = append(seeds, movieID)
+		}
+	}
+	sort.Slice(seeds, func(i, j int) bool {
+		a, b := profile.Ratings[seeds[i]], profile.Ratings[seeds[j]]
+		if a != b {
+			return a > b
+		}
+		return seeds[i] < seeds[j]
+	})
+	seeds = append(seeds, profile.Watchlist...)
+
+	best := make(map[string]Suggestion)
+	for _, seed := range seeds {
+		for _, s := range similar(seed, limit) {
+			if old, ok := best[s.MovieID]; !ok || s.Score > old.Score {
+				best[s.MovieID] = Suggestion{MovieID: s.MovieID, Score: s.Score, Reason: models.ReasonSimilar, BecauseOf: seed}
+			}
+		}
+	}
+	result := make([]Suggestion, 0, len(best))
+	for _, s := range best {
+		result = append(result, s)
+	}
+	sortSuggestions(result)
+	return result
+}
+
+func sortSuggestions(list []Suggestion) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		return list[i].MovieID < list[j].MovieID
+	})
+}
+
+func meanOf(ratings map[string]float64) float64 {
+	if len(ratings) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, value := range ratings {
+		sum += value
+	}
+	return sum / float64(len(ratings))
+}
+
+// sortedRows возвращает строки матрицы оценок в порядке идентификаторов зрителей,
+// чтобы построение было воспроизводимым
+func sortedRows(matrix map[string]map[string]float64) []map[string]float64 {
+	users := make([]string, 0, len(matrix))
+	for userID := range matrix {
+		users = append(users, userID)
+	}
+	sort.Strings(users)
+	rows := make([]map[string]float64, len(users))
+	for i, userID := range users {
+		rows[i] = matrix[userID]
+	}
+	return rows
+}
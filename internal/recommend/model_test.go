@@ -0,0 +1,91 @@
+package recommend
+
+import (
+	"context"
+	"testing"
+
+	"movie-catalog/internal/models"
+)
+
+func always(string) bool { return true }
+
+func TestRecommendCollaborative(t *testing.T) {
+	// Любители фантастики ставят высокие оценки inception, interstellar и tenet,
+	// любители комедий — 1+1 и lion-king
+	matrix := map[string]map[string]float64{
+		"a": {"inception": 9, "interstellar": 10, "tenet": 9, "intouchables": 3},
+		"b": {"inception": 10, "interstellar": 9, "tenet": 8, "lion-king": 2},
+		"c": {"inception": 3, "interstellar": 2, "intouchables": 9, "lion-king": 10},
+		"d": {"inception": 2, "tenet": 3, "intouchables": 10, "lion-king": 9},
+	}
+	model, err := Build(context.Background(), matrix, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile := Profile{Ratings: map[string]float64{"inception": 10, "interstellar": 9, "intouchables": 2}}
+	got := model.Recommend(profile, 1, nil, always)
+	if len(got) != 1 || got[0].MovieID != "tenet" || got[0].Reason != models.ReasonCollaborative {
+		t.Errorf("Recommend = %+v, want tenet by %s", got, models.ReasonCollaborative)
+	}
+}
+
+func TestRecommendColdStart(t *testing.T) {
+	matrix := map[string]map[string]float64{
+		"a": {"inception": 9, "lion-king": 6},
+		"b": {"inception": 10, "lion-king": 5},
+	}
+	model, err := Build(context.Background(), matrix, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	similar := func(movieID string, limit int) []Suggestion {
+		if movieID == "interstellar" {
+			return []Suggestion{{MovieID: "tenet", Score: 0.5}, {MovieID: "inception", Score: 0.4}}
+		}
+		return nil
+	}
+
+	// Новый зритель отложил один фильм: сначала похожие на него, затем популярные,
+	// без повторов и без самого отложенного фильма
+	got := model.Recommend(Profile{Watchlist: []string{"interstellar"}}, 10, similar, always)
+	want := []struct{ id, reason string }{
+		{"tenet", models.ReasonSimilar},
+		{"inception", models.ReasonSimilar},
+		{"lion-king", models.ReasonPopular},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Recommend = %+v, want %d movies", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].MovieID != w.id || got[i].Reason != w.reason {
+			t.Errorf("Recommend[%d] = %s (%s), want %s (%s)", i, got[i].MovieID, got[i].Reason, w.id, w.reason)
+		}
+	}
+	if got[0].BecauseOf != "interstellar" {
+		t.Errorf("BecauseOf = %q, want interstellar", got[0].BecauseOf)
+	}
+}
+
+func TestPickFavorsHighlyRated(t *testing.T) {
+	matrix := map[string]map[string]float64{
+		"a": {"tenet": 10, "cats": 1},
+		"b": {"tenet": 10, "cats": 1},
+	}
+	model, err := Build(context.Background(), matrix, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Сглаженные оценки: cats ≈ 4.2, tenet ≈ 6.8; cats достаются первые ~28% отрезка [0, 1)
+	candidates := []string{"cats", "tenet"}
+	for random, want := range map[float64]string{0.1: "cats", 0.2: "cats", 0.4: "tenet", 0.99: "tenet"} {
+		got, ok := model.Pick(candidates, func() float64 { return random })
+		if !ok || got != want {
+			t.Errorf("Pick(%v) = %q, want %q", random, got, want)
+		}
+	}
+	if _, ok := model.Pick(nil, func() float64 { return 0 }); ok {
+		t.Error("Pick without candidates should fail")
+	}
+}
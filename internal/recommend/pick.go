@@ -0,0 +1,27 @@
+package recommend
+
+// Pick выбирает случайный фильм из candidates с вероятностью, пропорциональной
+// квадрату его средней оценки (Model.Rating): фильм с девяткой выпадает втрое чаще,
+// чем с пятёркой, но и слабые фильмы иногда попадаются. random возвращает число
+// из [0, 1), например rand.Float64. false — кандидатов нет.
+func (m *Model) Pick(candidates []string, random func() float64) (string, bool) {
+	if len(candidates) == 0 {
+		return "", false
+	}
+	weights := make([]float64, len(candidates))
+	var total float64
+	for i, movieID := range candidates {
+		rating := m.Rating(movieID)
+		weights[i] = rating * rating
+		total += weights[i]
+	}
+
+	target := random() * total
+	for i, weight := range weights {
+		if target < weight {
+			return candidates[i], true
+		}
+		target -= weight
+	}
+	return candidates[len(candidates)-1], true
+}
@@ -0,0 +1,60 @@
+package recommend
+
+import (
+	"context"
+	"time"
+)
+
+// throttleSlice — сколько работать подряд перед паузой
+const throttleSlice = 10 * time.Millisecond
+
+// Throttle ограничивает долю процессорного времени долгой работы в одной горутине:
+// после каждого отрезка работы длиной throttleSlice следует пауза, так что на работу
+// приходится не больше share времени. nil и share >= 1 не ограничивают.
+type Throttle struct {
+	share   float64
+	started time.Time
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// NewThrottle создаёт ограничитель с долей share от 0 до 1
+func NewThrottle(share float64) *Throttle {
+	return &Throttle{share: share, sleep: sleepContext}
+}
+
+// Step вызывается между небольшими порциями работы. Если отрезок работы исчерпан,
+// Step делает паузу. Возвращает ошибку ctx, если работа отменена.
+func (t *Throttle) Step(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if t == nil || t.share <= 0 || t.share >= 1 {
+		return nil
+	}
+	now := time.Now()
+	if t.started.IsZero() {
+		t.started = now
+		return nil
+	}
+	worked := now.Sub(t.started)
+	if worked < throttleSlice {
+		return nil
+	}
+	pause := time.Duration(float64(worked) * (1 - t.share) / t.share)
+	if err := t.sleep(ctx, pause); err != nil {
+		return err
+	}
+	t.started = time.Now()
+	return nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package recommend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// fakeSleep запоминает паузы ограничителя, не останавливая работу
+type fakeSleep struct {
+	paused time.Duration
+	pauses int
+}
+
+func (f *fakeSleep) sleep(ctx context.Context, d time.Duration) error {
+	f.paused += d
+	f.pauses++
+	return ctx.Err()
+}
+
+// staticSource — неизменные оценки для Job
+type staticSource map[string]map[string]float64
+
+func (s staticSource) Matrix() map[string]map[string]float64 { return s }
+func (s staticSource) Version() uint64                       { return 1 }
+
+func TestThrottleShare(t *testing.T) {
+	fake := &fakeSleep{}
+	throttle := NewThrottle(0.25)
+	throttle.sleep = fake.sleep
+
+	ctx := context.Background()
+	started := time.Now()
+	for time.Since(started) < 200*time.Millisecond {
+		if err := throttle.Step(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	worked := time.Since(started)
+
+	// При доле 0.25 на каждую единицу работы приходится три единицы паузы;
+	// последний неполный отрезок работы паузы ещё не получил
+	ratio := float64(fake.paused) / float64(worked)
+	if ratio < 2.7 || ratio > 3.1 {
+		t.Errorf("пауза/работа = %.2f (пауз %d на %v), ожидалось около 3", ratio, fake.pauses, worked)
+	}
+}
+
+func TestJobThrottlesLargeRow(t *testing.T) {
+	// Один зритель с сотнями оценок: раньше ограничитель проверялся только
+	// между строками и такая строка строилась без пауз
+	row := make(map[string]float64)
+	for i := 0; i < 600; i++ {
+		row[fmt.Sprintf("movie-%04d", i)] = float64(1 + i%10)
+	}
+	source := staticSource{"heavy": row, "light": {"movie-0001": 5, "movie-0002": 7}}
+
+	fake := &fakeSleep{}
+	job := NewJob(source, time.Hour, 0.5, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	job.sleep = fake.sleep
+
+	started := time.Now()
+	job.rebuild(context.Background())
+	worked := time.Since(started)
+
+	if !job.built {
+		t.Fatal("модель не построена")
+	}
+	if fake.pauses < 2 {
+		t.Fatalf("пауз %d за %v работы, ожидалось несколько", fake.pauses, worked)
+	}
+	// При доле 0.5 пауза примерно равна работе; отрезок после последней паузы не учтён
+	if ratio := float64(fake.paused) / float64(worked); ratio < 0.5 || ratio > 1.1 {
+		t.Errorf("пауза/работа = %.2f (пауз %d на %v), ожидалось около 1", ratio, fake.pauses, worked)
+	}
+}
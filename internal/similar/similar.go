@@ -0,0 +1,214 @@
+// Package similar подбирает похожие фильмы по содержанию: TF-IDF по русским описаниям
+// со стеммингом вместе с общей категорией, метками, людьми и близостью года выпуска
+package similar
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"movie-catalog/internal/models"
+)
+
+// Weights — вклад признаков в итоговую оценку сходства; в сумме дают 1
+type Weights struct {
+	// Text — косинусная близость описаний
+	Text float64
+	// Category — совпадение категории
+	Category float64
+	// Tags — доля общих меток (коэффициент Жаккара)
+	Tags float64
+	// People — доля общих режиссёров и актёров (коэффициент Жаккара)
+	People float64
+	// Year — близость годов выпуска
+	Year float64
+}
+
+// DefaultWeights — веса признаков по умолчанию: главное — описание и метки
+var DefaultWeights = Weights{Text: 0.45, Category: 0.1, Tags: 0.2, People: 0.15, Year: 0.1}
+
+// yearScale — разница в годах, при которой близость года падает в e раз
+const yearScale = 10.0
+
+// Index — неизменяемый индекс сходства фильмов каталога.
+// Строится заново после каждой перезагрузки каталога.
+type Index struct {
+	weights Weights
+	movies  []models.Movie
+	byID    map[string]int
+	vectors []map[string]float64
+	people  []map[string]bool
+}
+
+// Build строит индекс по фильмам с весами DefaultWeights
+func Build(movies []models.Movie) *Index {
+	return BuildWeighted(movies, DefaultWeights)
+}
+
+// BuildWeighted строит индекс по фильмам с заданными весами признаков
+func BuildWeighted(movies []models.Movie, weights Weights) *Index {
+	ix := &Index{
+		weights: weights,
+		movies:  movies,
+		byID:    make(map[string]int, len(movies)),
+		vectors: make([]map[string]float64, len(movies)),
+		people:  make([]map[string]bool, len(movies)),
+	}
+
+	// Частоты основ в каждом фильме и число фильмов с каждой основой
+	counts := make([]map[string]int, len(movies))
+	df := make(map[string]int)
+	for i, movie := range movies {
+		ix.byID[movie.ID] = i
+		counts[i] = make(map[string]int)
+		for _, term := range Terms(document(movie)) {
+			if counts[i][term] == 0 {
+				df[term]++
+			}
+			counts[i][term]++
+		}
+		ix.people[i] = make(map[string]bool)
+		for _, name := range append(append([]string(nil), movie.Directors...), movie.Cast...) {
+			ix.people[i][strings.ToLower(name)] = true
+		}
+	}
+
+	// Векторы TF-IDF с логарифмической частотой, нормированные по длине
+	n := float64(len(movies))
+	for i := range movies {
+		vector := make(map[string]float64, len(counts[i]))
+		var norm float64
+		for term, count := range counts[i] {
+			weight := (1 + math.Log(float64(count))) * math.Log(n/float64(df[term]))
+			if weight > 0 {
+				vector[term] = weight
+				norm += weight * weight
+			}
+		}
+		norm = math.Sqrt(norm)
+		for term := range vector {
+			vector[term] /= norm
+		}
+		ix.vectors[i] = vector
+	}
+	return ix
+}
+
+// document собирает текст фильма для индекса; название учитывается дважды
+func document(movie models.Movie) string {
+	return strings.Join([]string{movie.Title, movie.Title, movie.Tagline, movie.Description, movie.FullDescription}, " ")
+}
+
+// Similar возвращает не больше limit фильмов, похожих на фильм id, от самых похожих.
+// false — такого фильма нет в индексе.
+func (ix *Index) Similar(id string, limit int) ([]models.SimilarMovie, bool) {
+	i, ok := ix.byID[id]
+	if !ok {
+		return nil, false
+	}
+
+	result := make([]models.SimilarMovie, 0, len(ix.movies)-1)
+	for j, movie := range ix.movies {
+		if j == i {
+			continue
+		}
+		result = append(result, models.SimilarMovie{Movie: movie, Score: ix.score(i, j)})
+	}
+	sort.Slice(result, func(a, b int) bool {
+		if result[a].Score != result[b].Score {
+			return result[a].Score > result[b].Score
+		}
+		return result[a].ID < result[b].ID
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, true
+}
+
+// Score возвращает оценку сходства двух фильмов индекса от 0 до 1;
+// false — одного из фильмов нет в индексе
+func (ix *Index) Score(id, other string) (float64, bool) {
+	i, ok := ix.byID[id]
+	j, otherOK := ix.byID[other]
+	if !ok || !otherOK {
+		return 0, false
+	}
+	return ix.score(i, j), true
+}
+
+func (ix *Index) score(i, j int) float64 {
+	a, b := ix.movies[i], ix.movies[j]
+	score := ix.weights.Text * cosine(ix.vectors[i], ix.vectors[j])
+	if a.Category == b.Category {
+		score += ix.weights.Category
+	}
+	score += ix.weights.Tags * jaccard(set(a.Tags), set(b.Tags))
+	score += ix.weights.People * jaccard(ix.people[i], ix.people[j])
+	score += ix.weights.Year * math.Exp(-math.Abs(float64(a.Year-b.Year))/yearScale)
+	// Округление убирает погрешности суммирования, чтобы порядок был стабильным
+	return math.Round(score*1e6) / 1e6
+}
+
+// cosine — скалярное произведение нормированных векторов
+func cosine(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	var sum float64
+	for term, weight := range a {
+		sum += weight * b[term]
+	}
+	return sum
+}
+
+func set(values []string) map[string]bool {
+	result := make(map[string]bool, len(values))
+	for _, v := range values {
+		result[v] = true
+	}
+	return result
+}
+
+// jaccard — доля общих элементов двух множеств; 0 для двух пустых
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for v := range a {
+		if b[v] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// Terms разбивает текст на слова, отбрасывает стоп-слова и однобуквенные слова
+// и возвращает основы оставшихся
+func Terms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.ReplaceAll(word, "ё", "е")
+		if len([]rune(word)) < 2 || stopWords[word] {
+			continue
+		}
+		terms = append(terms, Stem(word))
+	}
+	return terms
+}
+
+// stopWords — частые служебные слова, не влияющие на тему описания
+var stopWords = set(strings.Fields(`
+	а без более бы был была были было быть в вам вас ведь во вот впрочем все всегда всего всех всю вы
+	где да даже для до его ее ей ему если есть еще же за зачем здесь и из или им их к как какая какой
+	когда кто ли либо между меня мне много может можно мой моя мы на над надо наконец нас не него нее
+	ней нельзя нет ни нибудь никогда ним них ничего но ну о об один он она они опять от очень перед по
+	под после потом потому почти при про раз разве с сам свою себе себя сейчас со совсем так также
+	такой там тебя тем теперь то тогда того тоже только том тот три ту тут ты у уж уже хоть чего чем
+	через что чтоб чтобы чуть эти этого этой этом этот эту я
+`))
@@ -0,0 +1,72 @@
+package similar
+
+import (
+	"testing"
+
+	"movie-catalog/internal/models"
+)
+
+func TestStem(t *testing.T) {
+	cases := map[string]string{
+		"космонавтов": "космонавт",
+		"красивая":    "красив",
+		"путешествия": "путешеств",
+		"путешествие": "путешеств",
+		"времени":     "времен",
+		"время":       "врем",
+		"важнейший":   "важн",
+		"длинный":     "длин",
+		"настоящий":   "настоя",
+		"спасаясь":    "спас",
+		"прочитав":    "прочита",
+		"ёлки":        "елк",
+		"возможность": "возможн",
+		"космос":      "космос",
+		"и":           "и",
+	}
+	for word, want := range cases {
+		if got := Stem(word); got != want {
+			t.Errorf("Stem(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestTermsSkipStopWords(t *testing.T) {
+	got := Terms("Он и она — путешествия во времени!")
+	want := []string{"путешеств", "времен"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Terms = %q, want %q", got, want)
+	}
+}
+
+func TestSimilarRanksSharedThemes(t *testing.T) {
+	movies := []models.Movie{
+		{ID: "tenet", Title: "Довод", Year: 2020, Category: "fantasy", Tags: []string{"time-travel"},
+			Description: "Агент путешествует во времени, чтобы предотвратить войну", Directors: []string{"Кристофер Нолан"}},
+		{ID: "deja-vu", Title: "Дежавю", Year: 2006, Category: "fantasy", Tags: []string{"time-travel"},
+			Description: "Агент отправляется в прошлое, путешествие во времени помогает расследовать взрыв"},
+		{ID: "inception", Title: "Начало", Year: 2010, Category: "fantasy", Tags: []string{"mind-bending"},
+			Description: "Вор проникает в сны", Directors: []string{"Кристофер Нолан"}},
+		{ID: "green-book", Title: "Зелёная книга", Year: 2018, Category: "biography", Tags: []string{"true-story"},
+			Description: "Водитель и пианист едут по югу Америки"},
+	}
+	index := Build(movies)
+
+	similar, ok := index.Similar("tenet", 2)
+	if !ok {
+		t.Fatal("фильм tenet не найден в индексе")
+	}
+	if len(similar) != 2 || similar[0].ID != "deja-vu" || similar[1].ID != "inception" {
+		t.Fatalf("похожие на tenet: %+v", similar)
+	}
+	if similar[0].Score <= similar[1].Score {
+		t.Errorf("оценки не убывают: %v, %v", similar[0].Score, similar[1].Score)
+	}
+
+	if _, ok := index.Similar("missing", 5); ok {
+		t.Error("для неизвестного фильма ожидался false")
+	}
+	if score, _ := index.Score("tenet", "green-book"); score >= similar[1].Score {
+		t.Errorf("фильм другой категории без общих тем оценён слишком высоко: %v", score)
+	}
+}
@@ -0,0 +1,152 @@
+package similar
+
+import "strings"
+
+// Окончания для стеммера Портера (Snowball) для русского языка.
+// Окончания с пометкой preceded удаляются, только если перед ними стоит «а» или «я».
+type ending struct {
+	suffix   []rune
+	preceded bool
+}
+
+func endings(preceded, plain []string) []ending {
+	var list []ending
+	for _, s := range preceded {
+		list = append(list, ending{suffix: []rune(s), preceded: true})
+	}
+	for _, s := range plain {
+		list = append(list, ending{suffix: []rune(s)})
+	}
+	return list
+}
+
+var (
+	perfectiveGerund = endings(
+		[]string{"в", "вши", "вшись"},
+		[]string{"ив", "ивши", "ившись", "ыв", "ывши", "ывшись"},
+	)
+	adjective = endings(nil, []string{
+		"ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом",
+		"его", "ого", "ему", "ому", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею",
+	})
+	participle = endings(
+		[]string{"ем", "нн", "вш", "ющ", "щ"},
+		[]string{"ивш", "ывш", "ующ"},
+	)
+	reflexive = endings(nil, []string{"ся", "сь"})
+	verb      = endings(
+		[]string{"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет", "ют", "ны", "ть", "ешь", "нно"},
+		[]string{
+			"ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй", "ил", "ыл", "им", "ым", "ен",
+			"ило", "ыло", "ено", "ят", "ует", "уют", "ит", "ыт", "ены", "ить", "ыть", "ишь", "ую", "ю",
+		},
+	)
+	noun = endings(nil, []string{
+		"а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии", "и", "ией", "ей", "ой", "ий", "й",
+		"иям", "ям", "ием", "ем", "ам", "ом", "о", "у", "ах", "иях", "ях", "ы", "ь", "ию", "ью", "ю", "ия", "ья", "я",
+	})
+	derivational = endings(nil, []string{"ост", "ость"})
+	superlative  = endings(nil, []string{"ейш", "ейше"})
+)
+
+func isVowel(r rune) bool {
+	return strings.ContainsRune("аеиоуыэюя", r)
+}
+
+// regions возвращает начала областей RV и R2 алгоритма Snowball
+func regions(word []rune) (rv, r2 int) {
+	rv, r1 := len(word), len(word)
+	for i, r := range word {
+		if isVowel(r) {
+			rv = i + 1
+			break
+		}
+	}
+	for i := 1; i < len(word); i++ {
+		if !isVowel(word[i]) && isVowel(word[i-1]) {
+			r1 = i + 1
+			break
+		}
+	}
+	r2 = len(word)
+	for i := r1 + 1; i < len(word); i++ {
+		if !isVowel(word[i]) && isVowel(word[i-1]) {
+			r2 = i + 1
+			break
+		}
+	}
+	return rv, r2
+}
+
+// removeEnding удаляет самое длинное из окончаний list, которое целиком лежит не раньше from.
+// Если у найденного окончания не выполнено условие preceded, слово не меняется.
+func removeEnding(word []rune, from int, list []ending) ([]rune, bool) {
+	best := -1
+	for i, e := range list {
+		n := len(e.suffix)
+		if n > len(word)-from || (best >= 0 && n <= len(list[best].suffix)) {
+			continue
+		}
+		if string(word[len(word)-n:]) == string(e.suffix) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return word, false
+	}
+	start := len(word) - len(list[best].suffix)
+	if list[best].preceded {
+		if start-1 < from || (word[start-1] != 'а' && word[start-1] != 'я') {
+			return word, false
+		}
+	}
+	return word[:start], true
+}
+
+// Stem возвращает основу русского слова по алгоритму Портера (Snowball).
+// Слово должно быть в нижнем регистре; «ё» заменяется на «е».
+func Stem(word string) string {
+	w := []rune(strings.ReplaceAll(word, "ё", "е"))
+	rv, r2 := regions(w)
+	if rv >= len(w) {
+		return string(w)
+	}
+
+	// Шаг 1: деепричастие совершенного вида или возвратное окончание,
+	// затем прилагательное (с причастием), глагол или существительное
+	var ok bool
+	if w, ok = removeEnding(w, rv, perfectiveGerund); !ok {
+		w, _ = removeEnding(w, rv, reflexive)
+		if w, ok = removeEnding(w, rv, adjective); ok {
+			w, _ = removeEnding(w, rv, participle)
+		} else if w, ok = removeEnding(w, rv, verb); !ok {
+			w, _ = removeEnding(w, rv, noun)
+		}
+	}
+
+	// Шаг 2: конечное «и»
+	if len(w) > rv && w[len(w)-1] == 'и' {
+		w = w[:len(w)-1]
+	}
+
+	// Шаг 3: словообразовательные суффиксы в R2
+	if r2 < len(w) {
+		w, _ = removeEnding(w, r2, derivational)
+	}
+
+	// Шаг 4: превосходная степень, двойное «н» и мягкий знак
+	if w, ok = removeEnding(w, rv, superlative); ok || endsWith(w, rv, "нн") {
+		if endsWith(w, rv, "нн") {
+			w = w[:len(w)-1]
+		}
+	} else if endsWith(w, rv, "ь") {
+		w = w[:len(w)-1]
+	}
+	return string(w)
+}
+
+// endsWith сообщает, что слово оканчивается на suffix и окончание лежит не раньше from
+func endsWith(word []rune, from int, suffix string) bool {
+	s := []rune(suffix)
+	return len(word)-len(s) >= from && string(word[len(word)-len(s):]) == suffix
+}
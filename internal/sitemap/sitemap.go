@@ -0,0 +1,45 @@
+// Package sitemap формирует карту сайта в формате sitemaps.org
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// URL — адрес страницы в карте сайта
+type URL struct {
+	Loc string
+	// LastMod — дата последнего изменения страницы, необязательно
+	LastMod time.Time
+	// Priority — относительная важность страницы от 0 до 1; 0 — не указывать
+	Priority float64
+}
+
+// Marshal возвращает карту сайта с перечисленными адресами
+func Marshal(urls []URL) ([]byte, error) {
+	set := urlSet{}
+	for _, u := range urls {
+		entry := urlEntry{Loc: u.Loc, Priority: u.Priority}
+		if !u.LastMod.IsZero() {
+			entry.LastMod = u.LastMod.UTC().Format("2006-01-02")
+		}
+		set.URLs = append(set.URLs, entry)
+	}
+
+	body, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+type urlSet struct {
+	XMLName xml.Name   `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []urlEntry `xml:"url"`
+}
+
+type urlEntry struct {
+	Loc      string  `xml:"loc"`
+	LastMod  string  `xml:"lastmod,omitempty"`
+	Priority float64 `xml:"priority,omitempty"`
+}
@@ -1,5 +1,42 @@
 // Основной JavaScript файл для сайта каталога фильмов
 
+// Язык страницы и сообщения интерфейса, которые сервер встраивает в base.html
+const pageLocale = document.documentElement.lang || 'ru';
+// Префикс адресов страниц для языка, кроме русского: /en/movie/...
+const localePrefix = pageLocale === 'ru' ? '' : `/${pageLocale}`;
+const messages = loadMessages();
+
+// Функция для загрузки сообщений интерфейса из #i18n-messages
+function loadMessages() {
+  const element = document.getElementById('i18n-messages');
+  if (!element) return {};
+  try {
+    return JSON.parse(element.textContent);
+  } catch (e) {
+    console.warn('Не удалось разобрать сообщения интерфейса:', e);
+    return {};
+  }
+}
+
+// Функция для перевода сообщения по ключу; %s заменяется на аргумент
+function t(key, arg) {
+  const text = messages[key] || key;
+  return arg === undefined ? text : text.replace('%s', arg);
+}
+
+// Функция для выбора названия и описаний фильма на языке страницы
+// с откатом на русские значения
+function localizeMovie(movie) {
+  const translation = (movie.translations || {})[pageLocale];
+  if (!translation) return movie;
+  return Object.assign({}, movie, {
+    title: translation.title || movie.title,
+    description: translation.description || movie.description,
+    fullDescription: translation.fullDescription || movie.fullDescription,
+    tagline: translation.tagline || movie.tagline
+  });
+}
+
 document.addEventListener('DOMContentLoaded', function() {
   // Загрузка данных о фильмах
   fetch(`/static/data/movies.json?t=${Date.now()}`)
@@ -18,6 +55,8 @@ document.addEventListener('DOMContentLoaded', function() {
         }
         // Обработка кликов по ссылкам в хедере
         initializeHeaderLinks();
+        // Живое обновление карточек при изменении каталога
+        subscribeToCatalogEvents();
 
         // Инициализация модального окна
         const modal = document.getElementById("movieModal");
@@ -39,8 +78,8 @@ function initializeHeaderLinks() {
       const [path, hash] = href.split('#'); // Разделяем путь и якорь
       const categoryKey = hash;
 
-      // Если мы уже на странице /movies
-      if (window.location.pathname === '/movies' && categoryKey) {
+      // Если мы уже на странице /movies (в том числе с префиксом языка, /en/movies)
+      if (window.location.pathname === path && categoryKey) {
         e.preventDefault(); // Предотвращаем переход по ссылке
         scrollToCategory(categoryKey); // Скроллим к категории
       }
@@ -69,60 +108,132 @@ function displayMoviesByCategory(moviesByCategory) {
 
   // Отображаем фильмы по категориям
   Object.keys(moviesByCategory).forEach((categoryKey, index) => {
-    const categoryName = getCategoryDisplayName(categoryKey);
-    const movies = moviesByCategory[categoryKey];
-
-    // Создаем секцию для категории
-    const categorySection = document.createElement('section');
-    categorySection.className = 'category-section mb-12';
+    const categorySection = createCategorySection(categoryKey, moviesByCategory[categoryKey]);
     categorySection.style.animationDelay = `${0.1 * index}s`;
-    categorySection.dataset.category = categoryKey; // Добавляем атрибут для поиска
+    mainContainer.appendChild(categorySection);
+  });
+}
 
-    // Добавляем заголовок категории
-    const categoryTitle = document.createElement('h2');
-    categoryTitle.className = 'category-title text-2xl font-bold mb-6';
-    categoryTitle.textContent = categoryName;
-    categorySection.appendChild(categoryTitle);
+// Функция для создания секции категории со слайдером фильмов
+function createCategorySection(categoryKey, movies) {
+  const categoryName = getCategoryDisplayName(categoryKey);
 
-    // Создаем слайдер для фильмов
-    const sliderContainer = document.createElement('div');
-    sliderContainer.className = 'slider-container relative';
+  // Создаем секцию для категории
+  const categorySection = document.createElement('section');
+  categorySection.className = 'category-section mb-12';
+  categorySection.dataset.category = categoryKey; // Добавляем атрибут для поиска
 
-    const movieSlider = document.createElement('div');
-    movieSlider.className = 'movie-slider';
+  // Добавляем заголовок категории
+  const categoryTitle = document.createElement('h2');
+  categoryTitle.className = 'category-title text-2xl font-bold mb-6';
+  categoryTitle.textContent = categoryName;
+  categorySection.appendChild(categoryTitle);
 
-    // Добавляем фильмы в слайдер
-    movies.forEach(movie => {
-      const movieCard = createMovieCard(movie);
-      movieSlider.appendChild(movieCard);
-    });
+  // Создаем слайдер для фильмов
+  const sliderContainer = document.createElement('div');
+  sliderContainer.className = 'slider-container relative';
 
-    // Добавляем кнопки навигации слайдера
-    const prevButton = document.createElement('div');
-    prevButton.className = 'slider-nav slider-nav-prev';
-    prevButton.innerHTML = '<';
-    prevButton.addEventListener('click', () => {
-      movieSlider.scrollBy({ left: -600, behavior: 'smooth' });
-    });
+  const movieSlider = document.createElement('div');
+  movieSlider.className = 'movie-slider';
 
-    const nextButton = document.createElement('div');
-    nextButton.className = 'slider-nav slider-nav-next';
-    nextButton.innerHTML = '>';
-    nextButton.addEventListener('click', () => {
-      movieSlider.scrollBy({ left: 600, behavior: 'smooth' });
-    });
+  // Добавляем фильмы в слайдер
+  movies.forEach(movie => {
+    const movieCard = createMovieCard(movie);
+    movieSlider.appendChild(movieCard);
+  });
 
-    sliderContainer.appendChild(movieSlider);
-    sliderContainer.appendChild(prevButton);
-    sliderContainer.appendChild(nextButton);
+  // Добавляем кнопки навигации слайдера
+  const prevButton = document.createElement('div');
+  prevButton.className = 'slider-nav slider-nav-prev';
+  prevButton.innerHTML = '<';
+  prevButton.addEventListener('click', () => {
+    movieSlider.scrollBy({ left: -600, behavior: 'smooth' });
+  });
 
-    categorySection.appendChild(sliderContainer);
-    mainContainer.appendChild(categorySection);
+  const nextButton = document.createElement('div');
+  nextButton.className = 'slider-nav slider-nav-next';
+  nextButton.innerHTML = '>';
+  nextButton.addEventListener('click', () => {
+    movieSlider.scrollBy({ left: 600, behavior: 'smooth' });
+  });
+
+  sliderContainer.appendChild(movieSlider);
+  sliderContainer.appendChild(prevButton);
+  sliderContainer.appendChild(nextButton);
+
+  categorySection.appendChild(sliderContainer);
+  return categorySection;
+}
+
+// Функция для подписки на изменения каталога: карточки добавляются, обновляются
+// и удаляются на месте, без перезагрузки страницы
+function subscribeToCatalogEvents() {
+  if (!window.EventSource || !document.getElementById('movies-container')) return;
+
+  const source = new EventSource('/api/events');
+
+  source.addEventListener('movie.added', event => {
+    addMovieCard(JSON.parse(event.data));
+  });
+
+  source.addEventListener('movie.updated', event => {
+    const movie = JSON.parse(event.data);
+    const existing = findMovieItem(movie.id);
+    // Если фильм остался в той же категории, заменяем карточку на месте
+    if (existing && existing.closest('.category-section').dataset.category === movie.category) {
+      existing.replaceWith(createMovieCard(movie));
+      return;
+    }
+    removeMovieCard(movie.id);
+    addMovieCard(movie);
+  });
+
+  source.addEventListener('movie.removed', event => {
+    removeMovieCard(JSON.parse(event.data).id);
   });
+
+  source.onerror = () => {
+    // EventSource переподключается сам; закрытое соединение означает отказ сервера
+    if (source.readyState === EventSource.CLOSED) {
+      console.warn('Подписка на изменения каталога прекращена');
+    }
+  };
+}
+
+// Функция для поиска карточки фильма по идентификатору
+function findMovieItem(movieId) {
+  const card = document.querySelector(`.movie-card[data-movie-id="${CSS.escape(movieId)}"]`);
+  return card ? card.closest('.movie-slider-item') : null;
+}
+
+// Функция для добавления карточки в слайдер категории, при необходимости с новой секцией
+function addMovieCard(movie) {
+  if (findMovieItem(movie.id)) return;
+
+  let categorySection = document.querySelector(`.category-section[data-category="${CSS.escape(movie.category)}"]`);
+  if (!categorySection) {
+    categorySection = createCategorySection(movie.category, []);
+    categorySection.classList.add('visible');
+    document.getElementById('movies-container').appendChild(categorySection);
+  }
+  categorySection.querySelector('.movie-slider').appendChild(createMovieCard(movie));
+}
+
+// Функция для удаления карточки; опустевшая секция категории убирается целиком
+function removeMovieCard(movieId) {
+  const item = findMovieItem(movieId);
+  if (!item) return;
+
+  const categorySection = item.closest('.category-section');
+  item.remove();
+  if (categorySection && !categorySection.querySelector('.movie-slider-item')) {
+    categorySection.remove();
+  }
 }
 
 // Функция для создания карточки фильма
 function createMovieCard(movie) {
+  movie = localizeMovie(movie);
   const movieItem = document.createElement('div');
   movieItem.className = 'movie-slider-item';
 
@@ -135,8 +246,8 @@ function createMovieCard(movie) {
   posterContainer.className = 'movie-poster';
 
   const posterImg = document.createElement('img');
-  posterImg.src = movie.imagePath || '/static/images/movies/placeholder.jpg';
-  posterImg.alt = `Постер фильма "${movie.title}"`;
+  posterImg.src = safeUrl(movie.imagePath, '/static/images/movies/placeholder.jpg');
+  posterImg.alt = t('js.poster_alt', movie.title);
   posterImg.loading = 'lazy';
 
   // Создаем оверлей для затемнения при наведении
@@ -160,7 +271,7 @@ function createMovieCard(movie) {
 
   const description = document.createElement('div');
   description.className = 'movie-description';
-  description.textContent = movie.description || 'Описание отсутствует';
+  description.textContent = movie.description || t('js.no_description');
 
   infoContainer.appendChild(title);
   infoContainer.appendChild(year);
@@ -180,7 +291,7 @@ function createMovieCard(movie) {
 
   const tooltipDescription = document.createElement('div');
   tooltipDescription.className = 'tooltip-description';
-  tooltipDescription.textContent = movie.fullDescription || movie.description || 'Подробное описание отсутствует';
+  tooltipDescription.textContent = movie.fullDescription || movie.description || t('js.no_full_description');
 
   tooltip.appendChild(tooltipTitle);
   tooltip.appendChild(tooltipYear);
@@ -215,85 +326,401 @@ function createMovieCard(movie) {
   // Открытие модального окна при клике
   movieCard.addEventListener('click', function(e) {
     e.stopPropagation(); // Предотвращаем всплытие события
+    showMovieModal(movie, e.clientX, e.clientY);
+  });
+
+  return movieItem;
+}
 
-    const modal = document.getElementById("movieModal");
+// Функция для показа фильма в модальном окне рядом с точкой (x, y) в координатах окна
+function showMovieModal(movie, x, y) {
+  const modal = document.getElementById("movieModal") || createMovieModal();
 
-    // Заполняем модальное окно информацией о фильме
-    document.getElementById("modalTitle").textContent = movie.title;
-    document.getElementById("modalYear").textContent = `Год выпуска: ${movie.year}`;
-    document.getElementById("modalDescription").textContent = movie.fullDescription || movie.description || 'Подробное описание отсутствует';
+  // Заполняем модальное окно информацией о фильме
+  document.getElementById("modalTitle").textContent = movie.title;
+  document.getElementById("modalYear").textContent = t('js.year', movie.year);
+  document.getElementById("modalFacts").textContent = movieFacts(movie).join('\n');
+  document.getElementById("modalDescription").textContent = movie.fullDescription || movie.description || t('js.no_full_description');
 
-    const modalImage = document.getElementById("modalImage");
-    if (modalImage) {
-      modalImage.src = movie.imagePath || '/static/images/placeholder.jpg';
-      modalImage.alt = `Постер фильма "${movie.title}"`;
-    }
+  const modalImage = document.getElementById("modalImage");
+  if (modalImage) {
+    modalImage.src = safeUrl(movie.imagePath, '/static/images/placeholder.jpg');
+    modalImage.alt = t('js.poster_alt', movie.title);
+  }
 
-    // Добавляем ссылку, если она есть
-    const modalBody = modal.querySelector(".modal-body");
-    let linkElement = modal.querySelector(".modal-link"); // Проверяем, есть ли уже ссылка
-    if (movie.link) {
-      if (!linkElement) {
-        // Если элемента ссылки ещё нет, создаём его
-        linkElement = document.createElement("a");
-        linkElement.className = "modal-link";
-        linkElement.target = "_blank"; // Открывать в новой вкладке
-        linkElement.rel = "noopener noreferrer"; // Безопасность
-        modalBody.appendChild(linkElement);
-      }
-      linkElement.href = movie.link;
-      linkElement.textContent = "Смотреть на Кинопоиске"; // Или другой текст
-    } else if (linkElement) {
-      // Если ссылки нет в данных, удаляем элемент, если он был
-      linkElement.remove();
-    }
+  // Добавляем ссылки на внешние сервисы, если они есть
+  const modalBody = modal.querySelector(".modal-body");
+  modal.querySelectorAll(".modal-link").forEach(link => link.remove());
+  externalLinks(movie).forEach(({ label, url }) => {
+    const linkElement = document.createElement("a");
+    linkElement.className = "modal-link";
+    linkElement.target = "_blank"; // Открывать в новой вкладке
+    linkElement.rel = "noopener noreferrer"; // Безопасность
+    linkElement.href = url;
+    linkElement.textContent = label;
+    modalBody.appendChild(linkElement);
+  });
+  showSimilarMovies(movie.id);
+
+  // Позиционируем модальное окно рядом с курсором
+  const modalWidth = modal.offsetWidth;
+  const modalHeight = modal.offsetHeight;
+  const viewportWidth = window.innerWidth;
+  const viewportHeight = window.innerHeight;
 
-    // Позиционируем модальное окно рядом с курсором
-    const modalWidth = modal.offsetWidth;
-    const modalHeight = modal.offsetHeight;
-    const viewportWidth = window.innerWidth;
-    const viewportHeight = window.innerHeight;
+  let left = x + 10; // Смещение вправо от курсора на 10px
+  let top = y + window.scrollY + 10; // Смещение вниз от курсора на 10px с учетом прокрутки
 
-    let left = e.clientX + 10; // Смещение вправо от курсора на 10px
-    let top = e.clientY + window.scrollY + 10; // Смещение вниз от курсора на 10px с учетом прокрутки
+  // Корректируем позицию, чтобы окно не выходило за пределы экрана
+  if (left + modalWidth > viewportWidth - 10) {
+    left = x - modalWidth - 10; // Показываем слева от курсора, если не помещается справа
+  }
+  if (top + modalHeight > window.scrollY + viewportHeight - 10) {
+    top = y + window.scrollY - modalHeight - 10; // Показываем выше курсора, если не помещается снизу
+  }
+  if (left < 10) {
+    left = 10; // Не даём выйти за левую границу
+  }
+  if (top < window.scrollY + 10) {
+    top = window.scrollY + 10; // Не даём выйти за верхнюю границу видимой области
+  }
+
+  modal.style.left = `${left}px`;
+  modal.style.top = `${top}px`;
+
+  // Показываем модальное окно
+  modal.classList.add("visible");
+}
 
-    // Корректируем позицию, чтобы окно не выходило за пределы экрана
-    if (left + modalWidth > viewportWidth - 10) {
-      left = e.clientX - modalWidth - 10; // Показываем слева от курсора, если не помещается справа
+// Функция для кнопки «Что посмотреть?» в шапке: сервер выбирает случайный фильм,
+// чаще высоко оценённый, из тех, что зритель ещё не оценил, и он открывается в модальном окне
+function initializeRandomMovieButton() {
+  const button = document.querySelector('.random-movie-button');
+  if (!button) return;
+  button.addEventListener('click', e => {
+    e.stopPropagation();
+    const rect = button.getBoundingClientRect();
+    fetch('/api/v1/movies/random?excludeWatched=true', {
+      credentials: 'same-origin',
+      headers: { 'Accept-Language': pageLocale }
+    })
+        .then(response => response.ok ? response.json() : Promise.reject(new Error(`HTTP ${response.status}`)))
+        .then(({ data }) => showMovieModal(data, rect.left, rect.bottom))
+        .catch(error => console.warn('Не удалось выбрать фильм:', error));
+  });
+}
+
+document.addEventListener('DOMContentLoaded', initializeRandomMovieButton);
+
+// Функция для текста ошибки API: сообщение и подробности, если они есть
+function apiErrorMessage(body, fallback) {
+  const error = body && body.error;
+  if (!error) return fallback;
+  return error.details ? `${error.message}: ${error.details}` : error.message;
+}
+
+// Функция для отправки JSON в API: при ошибке промис отклоняется с текстом из ответа
+function sendJSON(method, url, body) {
+  return fetch(url, {
+    method,
+    credentials: 'same-origin',
+    headers: { 'Content-Type': 'application/json', 'Accept-Language': pageLocale },
+    body: body === undefined ? undefined : JSON.stringify(body)
+  }).then(response => response.json()
+      .catch(() => ({}))
+      .then(data => response.ok ? data : Promise.reject(new Error(apiErrorMessage(data, `HTTP ${response.status}`)))));
+}
+
+// Функция для формы создания голосования «Киновечер»: срок по умолчанию — через
+// несколько часов, после создания открывается страница голосования
+function initializeNightForm() {
+  const form = document.querySelector('.night-new-form');
+  if (!form) return;
+  const status = form.querySelector('.night-status');
+  const minMovies = Number(form.dataset.minMovies);
+  const maxMovies = Number(form.dataset.maxMovies);
+
+  // datetime-local ждёт местное время без часового пояса
+  const deadline = new Date(Date.now() + Number(form.dataset.defaultDuration) * 60000);
+  deadline.setSeconds(0, 0);
+  const local = new Date(deadline.getTime() - deadline.getTimezoneOffset() * 60000);
+  form.elements.deadline.value = local.toISOString().slice(0, 16);
+
+  form.addEventListener('submit', e => {
+    e.preventDefault();
+    const movieIds = Array.from(form.querySelectorAll('input[name="movie"]:checked'), input => input.value);
+    if (movieIds.length < minMovies || movieIds.length > maxMovies) {
+      status.textContent = t('js.night_pick_movies', minMovies);
+      return;
     }
-    if (top + modalHeight > window.scrollY + viewportHeight - 10) {
-      top = e.clientY + window.scrollY - modalHeight - 10; // Показываем выше курсора, если не помещается снизу
+    sendJSON('POST', '/api/v1/nights', {
+      title: form.elements.title.value,
+      method: form.elements.method.value,
+      movieIds,
+      deadline: new Date(form.elements.deadline.value).toISOString()
+    })
+        .then(({ data }) => {
+          window.location.href = `${localePrefix}/night/${encodeURIComponent(data.id)}`;
+        })
+        .catch(error => {
+          status.textContent = t('js.night_error', error.message);
+        });
+  });
+}
+
+// Функция для страницы голосования: отправка бюллетеня, досрочное завершение
+// и живые итоги по событиям /api/v1/nights/{id}/events
+function initializeMovieNight() {
+  const page = document.querySelector('.movie-night');
+  if (!page) return;
+  const nightId = encodeURIComponent(page.dataset.nightId);
+  const form = page.querySelector('.night-ballot');
+  const status = form.querySelector('.night-status');
+  const titleOf = movieId => {
+    const item = page.querySelector(`.night-candidate[data-movie-id="${CSS.escape(movieId)}"]`);
+    return item ? item.dataset.title : movieId;
+  };
+
+  page.querySelectorAll('time.local-time').forEach(time => {
+    time.textContent = new Date(time.dateTime).toLocaleString(pageLocale, { dateStyle: 'medium', timeStyle: 'short' });
+  });
+
+  const copyButton = page.querySelector('.night-copy');
+  if (copyButton) {
+    copyButton.addEventListener('click', () => {
+      const input = page.querySelector('.night-share-url');
+      navigator.clipboard.writeText(input.value)
+          .then(() => { status.textContent = t('js.night_copied'); })
+          .catch(() => input.select());
+    });
+  }
+
+  // Бюллетень: отмеченные фильмы или фильмы по возрастанию места
+  function choices() {
+    if (page.dataset.method === 'ranked') {
+      return Array.from(page.querySelectorAll('.night-candidate'))
+          .map(item => ({ id: item.dataset.movieId, rank: Number(item.querySelector('.night-rank').value) }))
+          .filter(choice => choice.rank > 0)
+          .sort((a, b) => a.rank - b.rank)
+          .map(choice => choice.id);
     }
-    if (left < 10) {
-      left = 10; // Не даём выйти за левую границу
+    return Array.from(page.querySelectorAll('.night-choice:checked'), input => input.value);
+  }
+
+  // Показывает итоги: голоса за фильмы, участников, лидера и победителя
+  function showResults(results) {
+    results.tally.forEach(({ movieId, votes }) => {
+      const item = page.querySelector(`.night-candidate[data-movie-id="${CSS.escape(movieId)}"] .night-votes`);
+      if (item) item.textContent = t('js.night_votes', votes);
+    });
+    page.querySelector('.night-voters').textContent = t('js.night_voters', results.voters);
+    page.querySelector('.night-participants').textContent = results.participants.join(', ');
+    const leader = page.querySelector('.night-leader');
+    if (leader) leader.textContent = results.leader ? t('js.night_leader', titleOf(results.leader)) : '';
+
+    if (results.night.closedAt) {
+      page.querySelector('.night-deadline').textContent = t('js.night_closed');
+      page.querySelectorAll('.night-share, .night-close, .night-submit, .night-rank, .night-choice, .night-ballot label')
+          .forEach(element => element.remove());
+      if (leader) leader.remove();
+      if (results.night.winner) {
+        const winner = page.querySelector('.night-winner');
+        const link = winner.querySelector('.night-winner-link');
+        link.href = `${localePrefix}/movie/${encodeURIComponent(results.night.winner)}`;
+        link.textContent = titleOf(results.night.winner);
+        winner.classList.remove('hidden');
+      }
     }
-    if (top < window.scrollY + 10) {
-      top = window.scrollY + 10; // Не даём выйти за верхнюю границу видимой области
+  }
+
+  form.addEventListener('submit', e => {
+    e.preventDefault();
+    const name = form.elements.name ? form.elements.name.value : '';
+    sendJSON('PUT', `/api/v1/nights/${nightId}/ballot`, { name, choices: choices() })
+        .then(({ data }) => {
+          status.textContent = t('js.night_saved');
+          showResults(data);
+        })
+        .catch(error => {
+          status.textContent = t('js.night_error', error.message);
+        });
+  });
+
+  const closeButton = page.querySelector('.night-close');
+  if (closeButton) {
+    closeButton.addEventListener('click', () => {
+      sendJSON('POST', `/api/v1/nights/${nightId}/close`)
+          .then(({ data }) => showResults(data))
+          .catch(error => {
+            status.textContent = t('js.night_error', error.message);
+          });
+    });
+  }
+
+  if (!window.EventSource) return;
+  const source = new EventSource(`/api/v1/nights/${nightId}/events`);
+  source.addEventListener('night.updated', event => showResults(JSON.parse(event.data)));
+  source.addEventListener('night.closed', event => {
+    showResults(JSON.parse(event.data));
+    source.close();
+  });
+}
+
+document.addEventListener('DOMContentLoaded', initializeNightForm);
+document.addEventListener('DOMContentLoaded', initializeMovieNight);
+
+// Количество похожих фильмов в модальном окне
+const modalSimilarLimit = 4;
+
+// Функция для загрузки похожих фильмов в модальное окно. Ответ приходит асинхронно,
+// поэтому он отбрасывается, если за это время открыли другой фильм
+function showSimilarMovies(movieId) {
+  const container = document.getElementById("modalSimilar");
+  if (!container) return;
+  container.textContent = '';
+  container.dataset.movieId = movieId;
+
+  fetch(`/api/v1/movies/${encodeURIComponent(movieId)}/similar?limit=${modalSimilarLimit}`, {
+    headers: { 'Accept-Language': pageLocale }
+  })
+      .then(response => response.ok ? response.json() : Promise.reject(new Error(`HTTP ${response.status}`)))
+      .then(({ data }) => {
+        if (container.dataset.movieId !== movieId || !data.length) return;
+        const heading = document.createElement("h3");
+        heading.className = "modal-similar-title";
+        heading.textContent = t('js.similar');
+        container.appendChild(heading);
+        data.forEach(similar => {
+          const link = document.createElement("a");
+          link.className = "modal-similar-link";
+          link.href = `${localePrefix}/movie/${encodeURIComponent(similar.id)}`;
+          link.textContent = `${similar.title} (${similar.year})`;
+          container.appendChild(link);
+        });
+      })
+      .catch(error => console.warn('Не удалось загрузить похожие фильмы:', error));
+}
+
+// Функция для оценки фильма и кнопки «Посмотреть позже» на странице фильма.
+// Зрителя узнают по cookie, которую сервер выдаёт при первой оценке или закладке
+function initializeRatingWidget() {
+  const widget = document.querySelector('.movie-rating');
+  if (!widget) return;
+  const movieId = encodeURIComponent(widget.dataset.movieId);
+  const ratingButtons = widget.querySelectorAll('.rating-button');
+  const clearButton = widget.querySelector('.rating-clear');
+  const watchlistButton = widget.querySelector('.watchlist-button');
+
+  function showRating(value) {
+    ratingButtons.forEach(button => {
+      const active = Number(button.dataset.rating) === value;
+      button.classList.toggle('is-active', active);
+      button.setAttribute('aria-pressed', String(active));
+    });
+    clearButton.classList.toggle('hidden', !value);
+  }
+
+  function send(method, url, body) {
+    const options = { method, credentials: 'same-origin', headers: {} };
+    if (body !== undefined) {
+      options.headers['Content-Type'] = 'application/json';
+      options.body = JSON.stringify(body);
     }
+    return fetch(url, options)
+        .then(response => response.ok ? response : Promise.reject(new Error(`HTTP ${response.status}`)));
+  }
 
-    modal.style.left = `${left}px`;
-    modal.style.top = `${top}px`;
+  ratingButtons.forEach(button => {
+    button.addEventListener('click', () => {
+      const value = Number(button.dataset.rating);
+      send('PUT', `/api/v1/me/ratings/${movieId}`, { rating: value })
+          .then(() => showRating(value))
+          .catch(error => console.warn('Не удалось сохранить оценку:', error));
+    });
+  });
 
-    // Показываем модальное окно
-    modal.classList.add("visible");
+  clearButton.addEventListener('click', () => {
+    send('DELETE', `/api/v1/me/ratings/${movieId}`)
+        .then(() => showRating(0))
+        .catch(error => console.warn('Не удалось убрать оценку:', error));
   });
 
-  return movieItem;
+  watchlistButton.addEventListener('click', () => {
+    const inWatchlist = watchlistButton.getAttribute('aria-pressed') === 'true';
+    send(inWatchlist ? 'DELETE' : 'PUT', `/api/v1/me/watchlist/${movieId}`)
+        .then(() => {
+          watchlistButton.classList.toggle('is-active', !inWatchlist);
+          watchlistButton.setAttribute('aria-pressed', String(!inWatchlist));
+          watchlistButton.textContent = inWatchlist ? watchlistButton.dataset.labelAdd : watchlistButton.dataset.labelRemove;
+        })
+        .catch(error => console.warn('Не удалось изменить список «Посмотреть позже»:', error));
+  });
+}
+
+document.addEventListener('DOMContentLoaded', initializeRatingWidget);
+
+// Функция для сбора строк со сведениями о фильме: оригинальное название,
+// продолжительность, возраст и страны, режиссёры, актёры. Пустые поля пропускаются
+function movieFacts(movie) {
+  const facts = [];
+  const summary = [
+    movie.originalTitle,
+    movie.runtime ? t('js.runtime', movie.runtime) : '',
+    movie.ageRating,
+    (movie.countries || []).join(', ')
+  ].filter(Boolean);
+  if (summary.length) facts.push(summary.join(' · '));
+  if ((movie.directors || []).length) facts.push(t('js.directors', movie.directors.join(', ')));
+  if ((movie.cast || []).length) facts.push(t('js.cast', movie.cast.join(', ')));
+  return facts;
+}
+
+// Внешние сервисы и адреса страниц фильма по идентификатору; подписи ссылок берутся из сообщений external.*.watch
+const externalServices = [
+  { provider: 'kinopoisk', url: id => `https://www.kinopoisk.ru/film/${id}/` },
+  { provider: 'imdb', url: id => `https://www.imdb.com/title/${id}/` },
+  { provider: 'tmdb', url: id => `https://www.themoviedb.org/movie/${id}` }
+];
+
+// Функция для сбора ссылок на фильм во внешних сервисах. Идентификатор Кинопоиска,
+// если он не указан в externalIds, берётся из поля link, как и на сервере
+function externalLinks(movie) {
+  const ids = Object.assign({}, movie.externalIds);
+  const kinopoisk = /kinopoisk\.ru\/(?:film|series)\/(\d+)/.exec(movie.link || '');
+  if (kinopoisk && !ids.kinopoisk) {
+    ids.kinopoisk = kinopoisk[1];
+  }
+
+  const links = externalServices
+      .filter(service => ids[service.provider])
+      .map(service => ({ label: t(`external.${service.provider}.watch`), url: service.url(encodeURIComponent(ids[service.provider])) }));
+
+  // Ссылка на другой сайт показывается как есть
+  const movieLink = safeUrl(movie.link, '');
+  if (!links.length && movieLink) {
+    links.push({ label: new URL(movieLink).hostname, url: movieLink });
+  }
+  return links;
+}
+
+// Функция для проверки адресов из данных о фильмах: допускаются только http(s)
+// и локальные пути, чтобы испорченный movies.json не подставил javascript: и т.п.
+function safeUrl(value, fallback) {
+  if (!value) return fallback;
+  try {
+    const url = new URL(value, window.location.origin);
+    if (url.protocol === 'http:' || url.protocol === 'https:') {
+      return url.href;
+    }
+  } catch (e) {
+    console.warn('Некорректный адрес в данных о фильме:', value);
+  }
+  return fallback;
 }
 
 // Функция для получения отображаемого имени категории
 function getCategoryDisplayName(categoryKey) {
-  const categoryNames = {
-    'drama': 'Драма',
-    'comedy': 'Комедия',
-    'fantasy': 'Фантастика и фэнтези',
-    'thriller': 'Триллер и детектив',
-    'biography': 'Биографический',
-    'historical': 'Исторический и военный',
-    'melodrama': 'Мелодрама'
-  };
-
-  return categoryNames[categoryKey] || categoryKey;
+  return messages[`category.${categoryKey}`] || categoryKey;
 }
 
 // Инициализация всплывающих подсказок
@@ -376,7 +803,7 @@ function createMovieModal() {
 
   const modalImage = document.createElement("img");
   modalImage.id = "modalImage";
-  modalImage.alt = "Постер фильма";
+  modalImage.alt = t('js.poster');
 
   const modalBody = document.createElement("div");
   modalBody.className = "modal-body";
@@ -385,20 +812,30 @@ function createMovieModal() {
   modalYear.id = "modalYear";
   modalYear.className = "modal-year";
 
+  const modalFacts = document.createElement("p");
+  modalFacts.id = "modalFacts";
+  modalFacts.className = "modal-facts";
+
   const modalDescription = document.createElement("p");
   modalDescription.id = "modalDescription";
   modalDescription.className = "modal-description";
 
+  const modalSimilar = document.createElement("div");
+  modalSimilar.id = "modalSimilar";
+  modalSimilar.className = "modal-similar";
+
   // Собираем модальное окно
   modalHeader.appendChild(modalTitle);
   modalImageContainer.appendChild(modalImage);
   modalBody.appendChild(modalYear);
+  modalBody.appendChild(modalFacts);
   modalBody.appendChild(modalDescription);
 
   modalContent.appendChild(closeBtn);
   modalContent.appendChild(modalHeader);
   modalContent.appendChild(modalImageContainer);
   modalContent.appendChild(modalBody);
+  modalContent.appendChild(modalSimilar);
 
   modal.appendChild(modalContent);
 
@@ -407,6 +844,7 @@ function createMovieModal() {
 
   // Инициализируем обработчики событий сразу после создания
   initializeMovieModal(modal);
+  return modal;
 }
 
 // Инициализация обработчиков событий для модального окна